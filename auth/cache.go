@@ -0,0 +1,125 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/devicechain-io/dcctl/config"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// Name used for token cache when no context is selected.
+	DEFAULT_CACHE_NAME = "default"
+)
+
+var (
+	UNSAFE_NAME_CHARS = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// Token stored for a context along with information needed to refresh it. The scheme
+// and server the token was issued for are stored so it is never sent to other servers.
+type CachedToken struct {
+	TokenUrl string        `json:"tokenUrl"`
+	ClientId string        `json:"clientId"`
+	Username string        `json:"username,omitempty"`
+	Scheme   string        `json:"scheme"`
+	Server   string        `json:"server"`
+	Token    *oauth2.Token `json:"token"`
+}
+
+// Check whether the token was issued for the given scheme and server.
+func (c *CachedToken) IssuedFor(scheme string, server string) bool {
+	return c.Scheme != "" && c.Server != "" && c.Scheme == scheme && c.Server == server
+}
+
+// Get OAuth2 configuration used to refresh the cached token.
+func (c *CachedToken) OAuth2Config() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID: c.ClientId,
+		Endpoint: oauth2.Endpoint{
+			TokenURL:  c.TokenUrl,
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+}
+
+// Get the directory where tokens are cached.
+func CacheDir() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tokens"), nil
+}
+
+// Get path of token cache file for the given context.
+func cachePath(name string) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		name = DEFAULT_CACHE_NAME
+	}
+	return filepath.Join(dir, UNSAFE_NAME_CHARS.ReplaceAllString(name, "_")+".json"), nil
+}
+
+// Load cached token for the given context. Returns nil if no token is cached.
+func LoadToken(name string) (*CachedToken, error) {
+	path, err := cachePath(name)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	cached := &CachedToken{}
+	err = json.Unmarshal(b, cached)
+	if err != nil {
+		return nil, err
+	}
+	return cached, nil
+}
+
+// Save token for the given context.
+func SaveToken(name string, cached *CachedToken) error {
+	path, err := cachePath(name)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0600)
+}
+
+// Remove cached token for the given context. Returns false if no token was cached.
+func RemoveToken(name string) (bool, error) {
+	path, err := cachePath(name)
+	if err != nil {
+		return false, err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,178 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// Grant type used when polling for device authorization.
+	GRANT_TYPE_DEVICE_CODE = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+// Keycloak realm used to authenticate users.
+type Realm struct {
+	BaseUrl  string
+	Realm    string
+	ClientId string
+}
+
+// Get the OpenID Connect base url for the realm.
+func (r *Realm) openIdUrl() string {
+	return fmt.Sprintf("%s/realms/%s/protocol/openid-connect", strings.TrimSuffix(r.BaseUrl, "/"), r.Realm)
+}
+
+// Get the token endpoint for the realm.
+func (r *Realm) TokenUrl() string {
+	return r.openIdUrl() + "/token"
+}
+
+// Get the device authorization endpoint for the realm.
+func (r *Realm) DeviceAuthUrl() string {
+	return r.openIdUrl() + "/auth/device"
+}
+
+// Get OAuth2 configuration for the realm.
+func (r *Realm) OAuth2Config() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID: r.ClientId,
+		Endpoint: oauth2.Endpoint{
+			TokenURL:  r.TokenUrl(),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+}
+
+// Authenticate with username and password (resource owner password grant).
+func (r *Realm) PasswordLogin(ctx context.Context, username string, password string) (*oauth2.Token, error) {
+	token, err := r.OAuth2Config().PasswordCredentialsToken(ctx, username, password)
+	if err != nil {
+		return nil, fmt.Errorf("login to realm '%s' failed: %v", r.Realm, err)
+	}
+	return token, nil
+}
+
+// Response from device authorization endpoint.
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationUri         string `json:"verification_uri"`
+	VerificationUriComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// Token or error returned from the token endpoint.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Post a form to an endpoint and decode the JSON response. Uses the client from
+// the context in the same way as the oauth2 package.
+func postForm(ctx context.Context, endpoint string, form url.Values, result interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	client, ok := ctx.Value(oauth2.HTTPClient).(*http.Client)
+	if !ok {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("unexpected response from %s (%d): %s", endpoint, resp.StatusCode, string(body))
+	}
+	return resp.StatusCode, nil
+}
+
+// Start device authorization flow for the realm.
+func (r *Realm) StartDeviceLogin(ctx context.Context) (*DeviceAuthorization, error) {
+	form := url.Values{"client_id": {r.ClientId}}
+	auth := &DeviceAuthorization{}
+	status, err := postForm(ctx, r.DeviceAuthUrl(), form, auth)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK || auth.DeviceCode == "" {
+		return nil, fmt.Errorf("device authorization for realm '%s' failed with status %d", r.Realm, status)
+	}
+	if auth.Interval <= 0 {
+		auth.Interval = 5
+	}
+	return auth, nil
+}
+
+// Poll the token endpoint until the user completes device authorization.
+func (r *Realm) WaitForDeviceLogin(ctx context.Context, auth *DeviceAuthorization) (*oauth2.Token, error) {
+	form := url.Values{
+		"grant_type":  {GRANT_TYPE_DEVICE_CODE},
+		"device_code": {auth.DeviceCode},
+		"client_id":   {r.ClientId},
+	}
+	interval := time.Duration(auth.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		resp := &tokenResponse{}
+		_, err := postForm(ctx, r.TokenUrl(), form, resp)
+		if err != nil {
+			return nil, err
+		}
+		switch resp.Error {
+		case "":
+			return &oauth2.Token{
+				AccessToken:  resp.AccessToken,
+				RefreshToken: resp.RefreshToken,
+				TokenType:    resp.TokenType,
+				Expiry:       time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second),
+			}, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		case "expired_token":
+			return nil, errors.New("device code expired before login was completed")
+		case "access_denied":
+			return nil, errors.New("login was denied")
+		default:
+			return nil, fmt.Errorf("device login failed: %s %s", resp.Error, resp.ErrorDescription)
+		}
+		if auth.ExpiresIn > 0 && time.Now().After(deadline) {
+			return nil, errors.New("device code expired before login was completed")
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// Token source that saves refreshed tokens back to the cache.
+type cachingTokenSource struct {
+	mu     sync.Mutex
+	name   string
+	cached *CachedToken
+	source oauth2.TokenSource
+}
+
+// Get a valid token, refreshing and caching it if necessary.
+func (s *cachingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, fmt.Errorf("unable to refresh access token (try 'dcctl login'): %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token.AccessToken != s.cached.Token.AccessToken {
+		s.cached.Token = token
+		if err := SaveToken(s.name, s.cached); err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
+}
+
+// Round tripper that adds a bearer token to each request.
+type BearerTransport struct {
+	Base   http.RoundTripper
+	Source oauth2.TokenSource
+}
+
+// Add authorization header and delegate to the base transport.
+func (t *BearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Source.Token()
+	if err != nil {
+		return nil, err
+	}
+	authreq := req.Clone(req.Context())
+	token.SetAuthHeader(authreq)
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(authreq)
+}
+
+// Wrap a transport so requests carry the token cached for the given context. The
+// base transport is returned unchanged if no token is cached or if the token was
+// issued for a different scheme or server than the one targeted.
+func NewTransportForContext(name string, scheme string, server string, base http.RoundTripper) (http.RoundTripper, error) {
+	cached, err := LoadToken(name)
+	if err != nil {
+		return nil, err
+	}
+	if cached == nil || cached.Token == nil || !cached.IssuedFor(scheme, server) {
+		return base, nil
+	}
+
+	// Token refresh uses the base transport so TLS and proxy settings apply.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: base})
+	return &BearerTransport{
+		Base: base,
+		Source: &cachingTokenSource{
+			name:   name,
+			cached: cached,
+			source: cached.OAuth2Config().TokenSource(ctx, cached.Token),
+		},
+	}, nil
+}
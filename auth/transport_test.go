@@ -0,0 +1,79 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestNewTransportForContextOnlyAuthorizesIssuingServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	err := SaveToken("dev", &CachedToken{
+		TokenUrl: "http://keycloak.invalid/token",
+		ClientId: "dcctl",
+		Scheme:   "https",
+		Server:   "dc.example.com",
+		Token:    &oauth2.Token{AccessToken: "secret", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		scheme string
+		server string
+		want   string
+	}{
+		{name: "issuing server", scheme: "https", server: "dc.example.com", want: "Bearer secret"},
+		{name: "other server", scheme: "https", server: "other.example.com", want: ""},
+		{name: "other scheme", scheme: "http", server: "dc.example.com", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("Authorization")
+			}))
+			defer server.Close()
+
+			transport, err := NewTransportForContext("dev", tt.scheme, tt.server, http.DefaultTransport)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if got != tt.want {
+				t.Errorf("authorization header = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTransportForContextIgnoresTokensWithoutServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	err := SaveToken("", &CachedToken{Token: &oauth2.Token{AccessToken: "secret"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := http.DefaultTransport
+	transport, err := NewTransportForContext("", "https", "dc.example.com", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport != base {
+		t.Error("expected base transport for token cached without a server")
+	}
+}
@@ -7,18 +7,16 @@ Proprietary and confidential.
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"regexp"
-	"strings"
+	"time"
 
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
-var (
-	DUP_SPACE = regexp.MustCompile(`\s+`)
-)
-
 // Create common command for creating DeviceChain resources
 var bootstrapCmd = &cobra.Command{
 	Use:   "bootstrap",
@@ -26,36 +24,122 @@ var bootstrapCmd = &cobra.Command{
 	Long:  `Bootstraps system microservices with example datasets`,
 }
 
-// Remove leading space and any cases of multiple spaces internally.
-func unspace(val string) *string {
-	cleaned := DUP_SPACE.ReplaceAllString(strings.TrimSpace(val), " ")
-	return &cleaned
+// Structured summary of a bootstrap operation.
+type BootstrapReport struct {
+	Dataset           string              `json:"dataset"`
+	DurationSeconds   float64             `json:"durationSeconds"`
+	EntitiesPerSecond float64             `json:"entitiesPerSecond"`
+	Outcomes          []gql.AssureOutcome `json:"outcomes"`
 }
 
-// Create a string pointer from a string.
-func s(val string) *string {
-	return &val
+// Render bootstrap outcomes as a table.
+func (r *BootstrapReport) Table() *output.Table {
+	table := output.NewTable(output.Column{Header: "KIND"}, output.Column{Header: "TOKEN"},
+		output.Column{Header: "STATUS"})
+	for _, outcome := range r.Outcomes {
+		table.AddRow(outcome.Kind, outcome.Token, outcome.Status)
+	}
+	return table
 }
 
 // Section header for bootstrap operation.
 func title(dataset string) {
-	fmt.Println(GreenUnderline(fmt.Sprintf("\nBootstrap Data for %s Dataset", dataset)))
+	fmt.Fprintln(output.Progress(), GreenUnderline(fmt.Sprintf("\nBootstrap Data for %s Dataset", dataset)))
 }
 
 // Section header for bootstrap operation.
 func header(model string, dataset string) {
-	fmt.Println(WhiteUnderline(fmt.Sprintf("\nCreate %s for %s Dataset", model, dataset)))
+	fmt.Fprintln(output.Progress(), WhiteUnderline(fmt.Sprintf("\nCreate %s for %s Dataset", model, dataset)))
 }
 
 // Footer for bootstrap operation.
 func footer(dataset string) {
-	fmt.Println(color.HiGreenString(fmt.Sprintf("\nBootstrap Completed for %s Dataset.", dataset)))
+	fmt.Fprintln(output.Progress(), color.HiGreenString(fmt.Sprintf("\nBootstrap Completed for %s Dataset.", dataset)))
+}
+
+// Footer for failed bootstrap operation.
+func failedFooter(dataset string) {
+	fmt.Fprintln(output.Progress(), color.HiRedString(fmt.Sprintf("\nBootstrap Failed for %s Dataset.", dataset)))
+}
+
+// Summary of outcomes for bootstrap operation.
+func summary(created int, found int, failed int, elapsed time.Duration, rate float64) {
+	fmt.Fprintln(output.Progress(), WhiteUnderline("\nSummary"))
+	fmt.Fprintf(output.Progress(), "Created: %s Found: %s Failed: %s\n",
+		color.HiGreenString("%d", created), color.HiGreenString("%d", found), color.HiRedString("%d", failed))
+	fmt.Fprintf(output.Progress(), "Processed %d entities in %s (%.1f entities/sec)\n",
+		created+found+failed, elapsed.Round(time.Millisecond), rate)
+}
+
+// Executes bootstrap operations according to the error policy.
+type bootstrapper struct {
+	dm              gql.DeviceManagementClient
+	continueOnError bool
+	concurrency     int
+	started         time.Time
+	manifest        *BootstrapManifest
+}
+
+// Create a bootstrapper based on command flags and other settings.
+func newBootstrapper(cmd *cobra.Command) (*bootstrapper, error) {
+	continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency < 1 {
+		return nil, errors.New("concurrency must be greater than zero")
+	}
+	dm, err := gql.NewDeviceManagementGraphQLClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &bootstrapper{
+		dm:              dm,
+		continueOnError: continueOnError,
+		concurrency:     concurrency,
+		started:         time.Now(),
+	}, nil
+}
+
+// Check the result of an assure operation. Returns an error if bootstrap should stop.
+func (b *bootstrapper) check(err error) error {
+	if err == nil || b.continueOnError {
+		return nil
+	}
+	return err
+}
+
+// Print summary of bootstrap outcomes and determine the overall result.
+func (b *bootstrapper) finish(dataset string, err error) error {
+	created, found, failed := b.dm.Tracker.Counts()
+	elapsed := time.Since(b.started)
+	rate := float64(created+found+failed) / elapsed.Seconds()
+	summary(created, found, failed, elapsed, rate)
+	if err == nil && failed == 0 {
+		footer(dataset)
+	} else {
+		failedFooter(dataset)
+	}
+
+	perr := output.PrintResult(&BootstrapReport{
+		Dataset:           dataset,
+		DurationSeconds:   elapsed.Seconds(),
+		EntitiesPerSecond: rate,
+		Outcomes:          b.dm.Tracker.Outcomes,
+	})
+	if err != nil {
+		return fmt.Errorf("bootstrap of %s dataset stopped: %v", dataset, err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("bootstrap of %s dataset failed for %d entities", dataset, failed)
+	}
+	return perr
 }
 
 func init() {
-	bootstrapCmd.PersistentFlags().StringP("server", "s", "localhost", "server hostname targeted for remote calls")
-	bootstrapCmd.PersistentFlags().StringP("instance", "i", "dc1", "instance id targeted for remote calls")
-	bootstrapCmd.PersistentFlags().StringP("tenant", "t", "tenant1", "tenant id targeted for remote calls")
+	addTargetFlags(bootstrapCmd.PersistentFlags())
+	bootstrapCmd.PersistentFlags().Bool("continue-on-error", false, "continue with remaining entities when an entity fails")
+	bootstrapCmd.PersistentFlags().Int("concurrency", 8, "maximum number of entities created in parallel")
+	bootstrapCmd.PersistentFlags().Int64("seed", DEFAULT_GENERATOR_SEED, "seed for generated values such as VINs and dates")
+	bootstrapCmd.PersistentFlags().Bool("random-seed", false, "use a random seed so that generated values differ between runs")
 
 	rootCmd.AddCommand(bootstrapCmd)
 }
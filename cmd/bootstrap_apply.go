@@ -0,0 +1,195 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Intialize command for applying dataset files
+var bootstrapApplyCmd = NewBootstrapApplyCommand()
+
+// Create command that will apply dataset files
+func NewBootstrapApplyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Bootstrap data from dataset files",
+		Long: `Bootstraps system microservices with data from a dataset file or a directory of
+dataset files. Entities that already exist are left unchanged.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, _ := cmd.Flags().GetStringArray("filename")
+			return bootstrapDatasetFiles(cmd, paths)
+		}}
+	cmd.Flags().StringArrayP("filename", "f", nil, "dataset file or directory (may be repeated)")
+	return cmd
+}
+
+// Layer of entities that only depend on entities in earlier layers.
+type applyLayer struct {
+	Name     string
+	Entities []*DatasetEntity
+}
+
+// Roles of kinds that only depend on roles in earlier layers.
+var dmLayerRoles = []struct {
+	name  string
+	roles []dmRole
+}{
+	{"Types", []dmRole{dmRoleType, dmRoleRelationshipType, dmRoleGroupRelationshipType}},
+	{"Entities and Groups", []dmRole{dmRoleEntity, dmRoleGroup}},
+	{"Relationships", []dmRole{dmRoleRelationship, dmRoleGroupRelationship}},
+}
+
+// Build the dependency graph of a dataset as layers: types before entities, entities and
+// groups before relationships and group relationships. Entities in a layer are independent.
+func (ds *Dataset) layers() []applyLayer {
+	layers := make([]applyLayer, 0, len(dmLayerRoles))
+	for _, layerRoles := range dmLayerRoles {
+		layer := applyLayer{Name: layerRoles.name, Entities: make([]*DatasetEntity, 0)}
+		for _, kind := range dmKinds {
+			for _, role := range layerRoles.roles {
+				if kind.Role != role {
+					continue
+				}
+				for _, entity := range ds.Entities {
+					if entity.Kind == kind {
+						layer.Entities = append(layer.Entities, entity)
+					}
+				}
+			}
+		}
+		if len(layer.Entities) > 0 {
+			layers = append(layers, layer)
+		}
+	}
+	return layers
+}
+
+// Assure all entities in a layer exist using a bounded pool of workers. No new work is
+// started once an error stops the bootstrap or the context is cancelled.
+func (b *bootstrapper) applyLayer(ctx context.Context, layer applyLayer) error {
+	var wg sync.WaitGroup
+	var once sync.Once
+	var stopErr error
+	stopped := make(chan struct{})
+	work := make(chan *DatasetEntity)
+	for i := 0; i < b.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entity := range work {
+				_, created, err := entity.Kind.create(ctx, &b.dm, entity.Request)
+				if created && b.manifest != nil {
+					b.manifest.Add(entity.Kind, entity.Token())
+				}
+				if err := b.check(err); err != nil {
+					once.Do(func() {
+						stopErr = err
+						close(stopped)
+					})
+				}
+			}
+		}()
+	}
+
+schedule:
+	for _, entity := range layer.Entities {
+		select {
+		case work <- entity:
+		case <-stopped:
+			break schedule
+		case <-ctx.Done():
+			break schedule
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return errors.New("interrupted")
+	}
+	return stopErr
+}
+
+// Assure all entities in the dataset exist, one dependency layer at a time.
+func applyDataset(ctx context.Context, b *bootstrapper, ds *Dataset) error {
+	for _, layer := range ds.layers() {
+		header(layer.Name, ds.Name)
+		if err := b.applyLayer(ctx, layer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Expand, validate and apply a dataset.
+func bootstrapDataset(cmd *cobra.Command, ds *Dataset) error {
+	gen, err := newGeneratorForCommand(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ds.expand(gen); err != nil {
+		return err
+	}
+	if err := ds.prepare(); err != nil {
+		return err
+	}
+	title(ds.Name)
+	b, err := newBootstrapper(cmd)
+	if err != nil {
+		return err
+	}
+	b.manifest, err = LoadBootstrapManifest(cmd, ds.Name)
+	if err != nil {
+		return err
+	}
+
+	// Stop cleanly on Ctrl-C.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err = applyDataset(ctx, b, ds)
+
+	// Record created entities even if bootstrap stopped so they may be reset.
+	if merr := b.manifest.Save(); merr != nil {
+		fmt.Fprintf(output.Progress(), color.HiRedString("Unable to save bootstrap manifest: %v\n"), merr)
+	}
+	return b.finish(ds.Name, err)
+}
+
+// Load and apply dataset files
+func bootstrapDatasetFiles(cmd *cobra.Command, paths []string) error {
+	if len(paths) == 0 {
+		return errors.New("no dataset files passed (use -f)")
+	}
+	var ds *Dataset
+	for _, path := range paths {
+		loaded, err := LoadDataset(path)
+		if err != nil {
+			return err
+		}
+		if ds == nil {
+			ds = loaded
+		} else {
+			ds.merge(loaded)
+		}
+	}
+	return bootstrapDataset(cmd, ds)
+}
+
+func init() {
+	bootstrapCmd.AddCommand(bootstrapApplyCmd)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Dataset with sections declared in reverse dependency order.
+const LAYERED_DATASET = `version: v1
+name: Layered
+deviceGroupRelationships:
+  - token: group-contains-d1
+deviceRelationships:
+  - token: d1-tracks-d2
+deviceGroups:
+  - token: group
+devices:
+  - token: d1
+  - token: d2
+assetGroupRelationshipTypes:
+  - token: contains
+deviceRelationshipTypes:
+  - token: tracks
+deviceTypes:
+  - token: sensor
+assets:
+  - token: a1
+`
+
+// Get the tokens of entities in each layer.
+func layerTokens(layers []applyLayer) map[string][]string {
+	tokens := make(map[string][]string)
+	for _, layer := range layers {
+		for _, entity := range layer.Entities {
+			tokens[layer.Name] = append(tokens[layer.Name], entity.Token())
+		}
+	}
+	return tokens
+}
+
+func TestDatasetLayers(t *testing.T) {
+	ds, err := ParseDataset([]byte(LAYERED_DATASET), "layered.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layers := ds.layers()
+	names := make([]string, 0, len(layers))
+	for _, layer := range layers {
+		names = append(names, layer.Name)
+	}
+	if want := []string{"Types", "Entities and Groups", "Relationships"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("layers = %v, want %v", names, want)
+	}
+
+	// Entities of a layer follow the order kinds are declared, then the order in the dataset.
+	want := map[string][]string{
+		"Types":               {"sensor", "tracks", "contains"},
+		"Entities and Groups": {"d1", "d2", "group", "a1"},
+		"Relationships":       {"d1-tracks-d2", "group-contains-d1"},
+	}
+	if got := layerTokens(layers); !reflect.DeepEqual(got, want) {
+		t.Errorf("layer entities = %v, want %v", got, want)
+	}
+}
+
+func TestDatasetLayersSkipEmpty(t *testing.T) {
+	ds, err := ParseDataset([]byte("version: v1\nname: Types\ndeviceTypes:\n  - token: sensor\n"), "types.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layers := ds.layers()
+	if len(layers) != 1 || layers[0].Name != "Types" {
+		t.Errorf("unexpected layers: %v", layerTokens(layers))
+	}
+}
+
+func TestReferencesPointAtLaterLayers(t *testing.T) {
+	for _, kind := range dmKinds {
+		for _, ref := range kind.referencedBy() {
+			if ref.Kind == nil {
+				t.Errorf("%s has a reference without a kind", kind.Name)
+				continue
+			}
+			if dmLayerIndex(ref.Kind) <= dmLayerIndex(kind) {
+				t.Errorf("%s (layer %d) is referenced by %s (layer %d) at %s", kind.Name, dmLayerIndex(kind),
+					ref.Kind.Name, dmLayerIndex(ref.Kind), ref.Path)
+			}
+		}
+	}
+}
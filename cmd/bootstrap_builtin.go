@@ -0,0 +1,132 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	DATASET_CONSTRUCTION   = "construction"
+	DATASET_SMART_BUILDING = "smart-building"
+	DATASET_COLD_CHAIN     = "cold-chain"
+	DATASET_FLEET          = "fleet"
+)
+
+// Summary of a dataset embedded in the binary.
+type BuiltinDataset struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Entities    int    `json:"entities"`
+}
+
+// List of built-in datasets.
+type BuiltinDatasetList struct {
+	Datasets []BuiltinDataset `json:"datasets"`
+}
+
+// Render built-in datasets as a table.
+func (l *BuiltinDatasetList) Table() *output.Table {
+	table := output.NewTable(output.Column{Header: "NAME"}, output.Column{Header: "TITLE"},
+		output.Column{Header: "ENTITIES"}, output.Column{Header: "DESCRIPTION", Wide: true})
+	for _, ds := range l.Datasets {
+		table.AddRow(ds.Name, ds.Title, strconv.Itoa(ds.Entities), ds.Description)
+	}
+	return table
+}
+
+// Get names of datasets embedded in the binary.
+func BuiltinDatasetNames() ([]string, error) {
+	files, err := fs.Glob(DatasetFS, "bootstrap/datasets/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, strings.TrimSuffix(path.Base(file), ".yaml"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Get summaries of datasets embedded in the binary. Entity counts include generated entities.
+func BuiltinDatasets() ([]BuiltinDataset, error) {
+	names, err := BuiltinDatasetNames()
+	if err != nil {
+		return nil, err
+	}
+	datasets := make([]BuiltinDataset, 0, len(names))
+	for _, name := range names {
+		ds, err := LoadEmbeddedDataset(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := ds.expand(NewGenerator(DEFAULT_GENERATOR_SEED)); err != nil {
+			return nil, fmt.Errorf("dataset '%s': %v", name, err)
+		}
+		datasets = append(datasets, BuiltinDataset{
+			Name:        name,
+			Title:       ds.Name,
+			Description: ds.Description,
+			Entities:    len(ds.Entities),
+		})
+	}
+	return datasets, nil
+}
+
+// Create command that bootstraps a built-in dataset.
+func NewBuiltinDatasetCommand(name string) *cobra.Command {
+	short := fmt.Sprintf("Bootstrap %s sample data", strings.ReplaceAll(name, "-", " "))
+	long := fmt.Sprintf("Bootstraps system microservices with the built-in %s dataset", name)
+	if ds, err := LoadEmbeddedDataset(name); err == nil && ds.Description != "" {
+		long = fmt.Sprintf("Bootstraps system microservices with %s", lowerFirst(ds.Description))
+	}
+	return &cobra.Command{
+		Use:          name,
+		Short:        short,
+		Long:         long,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := LoadEmbeddedDataset(name)
+			if err != nil {
+				return err
+			}
+			return bootstrapDataset(cmd, ds)
+		}}
+}
+
+// Intialize command for listing built-in datasets
+var bootstrapListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List built-in datasets",
+	Long:         `Lists datasets that are built into dcctl and may be bootstrapped by name`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		datasets, err := BuiltinDatasets()
+		if err != nil {
+			return err
+		}
+		return output.Print(&BuiltinDatasetList{Datasets: datasets})
+	},
+}
+
+func init() {
+	names, _ := BuiltinDatasetNames()
+	for _, name := range names {
+		bootstrapCmd.AddCommand(NewBuiltinDatasetCommand(name))
+	}
+	bootstrapCmd.AddCommand(bootstrapListCmd)
+}
@@ -0,0 +1,407 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Current version of the dataset schema.
+	DATASET_VERSION_V1 = "v1"
+)
+
+var (
+	//go:embed bootstrap/datasets/*
+	DatasetFS embed.FS
+)
+
+// Entity declared in a dataset. Fields use the same names as the create request for the kind.
+type DatasetEntity struct {
+	Kind    *dmKind
+	Fields  map[string]interface{}
+	Request interface{}
+}
+
+// Token of the entity.
+func (e *DatasetEntity) Token() string {
+	token, _ := e.Fields["token"].(string)
+	return token
+}
+
+// Generator that expands templated entities for each item and count.
+type DatasetGenerator struct {
+	Foreach  []map[string]interface{}            `json:"foreach,omitempty"`
+	Count    int                                 `json:"count,omitempty"`
+	Vars     map[string]string                   `json:"vars,omitempty"`
+	Sections map[string][]map[string]interface{} `json:"-"`
+}
+
+// Dataset that may be applied by the bootstrap command.
+type Dataset struct {
+	Version     string             `json:"version"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Generate    []DatasetGenerator `json:"generate,omitempty"`
+	Entities    []*DatasetEntity   `json:"-"`
+}
+
+// Name of the dataset section holding entities of the kind (e.g. deviceGroupRelationships).
+func (k *dmKind) section() string {
+	parts := strings.Split(k.Name, "-")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = upperFirst(parts[i])
+	}
+	return strings.Join(parts, "")
+}
+
+// Find a device management kind by dataset section name.
+func findDmKindBySection(section string) *dmKind {
+	for _, kind := range dmKinds {
+		if kind.section() == section {
+			return kind
+		}
+	}
+	return nil
+}
+
+// Split a document into known fields and entity sections.
+func splitSections(doc map[string]json.RawMessage, known map[string]bool,
+	source string) (map[string][]map[string]interface{}, error) {
+	sections := make(map[string][]map[string]interface{})
+	for key, raw := range doc {
+		if known[key] {
+			continue
+		}
+		if findDmKindBySection(key) == nil {
+			return nil, fmt.Errorf("%s: unknown dataset section '%s'", source, key)
+		}
+		entities := make([]map[string]interface{}, 0)
+		if err := json.Unmarshal(raw, &entities); err != nil {
+			return nil, fmt.Errorf("%s: section '%s' must be a list of entities: %v", source, key, err)
+		}
+		sections[key] = entities
+	}
+	return sections, nil
+}
+
+// Add entities from sections to the dataset in the order kinds are declared.
+func (ds *Dataset) addSections(sections map[string][]map[string]interface{}) {
+	for _, kind := range dmKinds {
+		for _, fields := range sections[kind.section()] {
+			ds.Entities = append(ds.Entities, &DatasetEntity{Kind: kind, Fields: fields})
+		}
+	}
+}
+
+// Parse a dataset from JSON or YAML content.
+func ParseDataset(content []byte, source string) (*Dataset, error) {
+	b, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+	doc := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("%s: dataset must be an object: %v", source, err)
+	}
+	ds := &Dataset{}
+	if err := json.Unmarshal(b, ds); err != nil {
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+	if ds.Version != DATASET_VERSION_V1 {
+		return nil, fmt.Errorf("%s: unsupported dataset version '%s' (expected %s)", source, ds.Version, DATASET_VERSION_V1)
+	}
+	sections, err := splitSections(doc, map[string]bool{"version": true, "name": true, "description": true, "generate": true}, source)
+	if err != nil {
+		return nil, err
+	}
+	ds.addSections(sections)
+
+	// Generators hold templated entity sections alongside their settings.
+	generators := make([]map[string]json.RawMessage, 0)
+	if raw, ok := doc["generate"]; ok {
+		if err := json.Unmarshal(raw, &generators); err != nil {
+			return nil, fmt.Errorf("%s: %v", source, err)
+		}
+	}
+	for i, generator := range generators {
+		sections, err := splitSections(generator, map[string]bool{"foreach": true, "count": true, "vars": true},
+			fmt.Sprintf("%s: generator %d", source, i+1))
+		if err != nil {
+			return nil, err
+		}
+		ds.Generate[i].Sections = sections
+	}
+	return ds, nil
+}
+
+// Load a dataset from a file or from all JSON and YAML files in a directory.
+func LoadDataset(path string) (*Dataset, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return ParseDataset(content, path)
+	}
+
+	files := make([]string, 0)
+	err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".yaml", ".yml", ".json":
+			if !d.IsDir() {
+				files = append(files, file)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no dataset files found in '%s'", path)
+	}
+	sort.Strings(files)
+	merged := &Dataset{Version: DATASET_VERSION_V1}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		ds, err := ParseDataset(content, file)
+		if err != nil {
+			return nil, err
+		}
+		merged.merge(ds)
+	}
+	if merged.Name == "" {
+		merged.Name = filepath.Base(path)
+	}
+	return merged, nil
+}
+
+// Load a dataset embedded in the binary.
+func LoadEmbeddedDataset(name string) (*Dataset, error) {
+	path := fmt.Sprintf("bootstrap/datasets/%s.yaml", name)
+	content, err := DatasetFS.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("dataset '%s' not found", name)
+	}
+	return ParseDataset(content, path)
+}
+
+// Merge entities and generators from another dataset. The first name found is kept.
+func (ds *Dataset) merge(other *Dataset) {
+	if ds.Name == "" {
+		ds.Name = other.Name
+	}
+	ds.Entities = append(ds.Entities, other.Entities...)
+	ds.Generate = append(ds.Generate, other.Generate...)
+}
+
+// Functions available to generator templates.
+func (ds *Dataset) templateFuncs(gen Generator) template.FuncMap {
+	return template.FuncMap{
+		"vin":        gen.Vin,
+		"int":        gen.Int,
+		"pick":       gen.Pick,
+		"date":       gen.Date,
+		"dateWithin": gen.DateWithin,
+		"lookup": func(section string, token string) (map[string]interface{}, error) {
+			kind := findDmKindBySection(section)
+			if kind == nil {
+				return nil, fmt.Errorf("unknown dataset section '%s'", section)
+			}
+			for _, entity := range ds.Entities {
+				if entity.Kind == kind && entity.Token() == token {
+					return entity.Fields, nil
+				}
+			}
+			return nil, fmt.Errorf("%s '%s' not found in dataset", kind.Title, token)
+		},
+	}
+}
+
+// Render a template string with the given data.
+func renderTemplate(funcs template.FuncMap, text string, data map[string]interface{}) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New("value").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// Render all strings in a value recursively.
+func renderValue(funcs template.FuncMap, value interface{}, data map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return renderTemplate(funcs, v, data)
+	case map[string]interface{}:
+		// Keys are rendered in a stable order so generated values are reproducible.
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		rendered := make(map[string]interface{}, len(v))
+		for _, key := range keys {
+			item := v[key]
+			r, err := renderValue(funcs, item, data)
+			if err != nil {
+				return nil, err
+			}
+			rendered[key] = r
+		}
+		return rendered, nil
+	case []interface{}:
+		rendered := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			r, err := renderValue(funcs, item, data)
+			if err != nil {
+				return nil, err
+			}
+			rendered = append(rendered, r)
+		}
+		return rendered, nil
+	}
+	return value, nil
+}
+
+// Expand generators into entities. Generated entities follow declared entities.
+func (ds *Dataset) expand(gen Generator) error {
+	funcs := ds.templateFuncs(gen)
+	for i, generator := range ds.Generate {
+		items := generator.Foreach
+		if len(items) == 0 {
+			items = []map[string]interface{}{{}}
+		}
+		count := generator.Count
+		if count <= 0 {
+			count = 1
+		}
+
+		// Variables are sorted so they are generated in a stable order.
+		names := make([]string, 0, len(generator.Vars))
+		for name := range generator.Vars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		sectionNames := make([]string, 0, len(generator.Sections))
+		for section := range generator.Sections {
+			sectionNames = append(sectionNames, section)
+		}
+		sort.Strings(sectionNames)
+
+		sections := make(map[string][]map[string]interface{})
+		for _, item := range items {
+			for index := 0; index < count; index++ {
+				data := map[string]interface{}{"index": index}
+				for key, value := range item {
+					data[key] = value
+				}
+				for _, name := range names {
+					value, err := renderTemplate(funcs, generator.Vars[name], data)
+					if err != nil {
+						return fmt.Errorf("generator %d: variable '%s': %v", i+1, name, err)
+					}
+					data[name] = value
+				}
+				for _, section := range sectionNames {
+					for _, entity := range generator.Sections[section] {
+						rendered, err := renderValue(funcs, entity, data)
+						if err != nil {
+							return fmt.Errorf("generator %d: %s: %v", i+1, section, err)
+						}
+						sections[section] = append(sections[section], rendered.(map[string]interface{}))
+					}
+				}
+			}
+		}
+		ds.addSections(sections)
+	}
+	ds.Generate = nil
+	return nil
+}
+
+// Default token for relationships that do not specify one (source-type-target).
+func defaultRelationshipToken(fields map[string]interface{}) string {
+	var source, target string
+	for key, value := range fields {
+		if strings.HasPrefix(key, "source") {
+			source, _ = value.(string)
+		}
+	}
+	relation, _ := fields["relationshipType"].(string)
+	targets, _ := fields["targets"].(map[string]interface{})
+	for _, value := range targets {
+		if str, ok := value.(string); ok && str != "" {
+			target = str
+		}
+	}
+	if source == "" || relation == "" || target == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s-%s-%s", source, relation, target)
+}
+
+// Build and validate create requests for all entities in the dataset.
+func (ds *Dataset) prepare() error {
+	tokens := make(map[string]bool)
+	for _, entity := range ds.Entities {
+		role := entity.Kind.Role
+		if entity.Token() == "" && (role == dmRoleRelationship || role == dmRoleGroupRelationship) {
+			entity.Fields["token"] = defaultRelationshipToken(entity.Fields)
+		}
+		b, err := json.Marshal(entity.Fields)
+		if err != nil {
+			return err
+		}
+		request := entity.Kind.newRequest()
+		if err := decodeRequest(b, request); err != nil {
+			return fmt.Errorf("invalid %s in dataset: %v", entity.Kind.Title, err)
+		}
+		if entity.Token() == "" {
+			return fmt.Errorf("%s in dataset has no token", entity.Kind.Title)
+		}
+		if err := checkRequiredFields(request, entity.Kind); err != nil {
+			return fmt.Errorf("%s '%s': %v", entity.Kind.Title, entity.Token(), err)
+		}
+		key := entity.Kind.Name + "/" + entity.Token()
+		if tokens[key] {
+			return fmt.Errorf("duplicate %s '%s' in dataset", entity.Kind.Title, entity.Token())
+		}
+		tokens[key] = true
+		entity.Request = request
+	}
+	return nil
+}
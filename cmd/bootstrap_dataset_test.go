@@ -0,0 +1,83 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDatasetErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "version", content: "version: v2\nname: Test\n", wantErr: "unsupported dataset version"},
+		{name: "unknown section", content: "version: v1\nname: Test\nwidgets:\n  - token: w1\n", wantErr: "unknown dataset section 'widgets'"},
+		{name: "section not a list", content: "version: v1\nname: Test\ndevices:\n  token: d1\n", wantErr: "must be a list of entities"},
+		{name: "unknown generator section", content: "version: v1\nname: Test\ngenerate:\n  - count: 2\n    widgets: []\n",
+			wantErr: "generator 1: unknown dataset section 'widgets'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDataset([]byte(tt.content), "test.yaml")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergedDatasetLayers(t *testing.T) {
+	relationships, err := ParseDataset([]byte("version: v1\nname: Relationships\ndeviceRelationships:\n  - token: d1-tracks-d2\n"), "a.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	types, err := ParseDataset([]byte("version: v1\nname: Types\ndeviceTypes:\n  - token: sensor\ndevices:\n  - token: d1\n"), "b.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Files merged in any order are still applied types first.
+	relationships.merge(types)
+	want := map[string][]string{
+		"Types":               {"sensor"},
+		"Entities and Groups": {"d1"},
+		"Relationships":       {"d1-tracks-d2"},
+	}
+	layers := relationships.layers()
+	if got := layerTokens(layers); !reflect.DeepEqual(got, want) {
+		t.Errorf("layer entities = %v, want %v", got, want)
+	}
+	if layers[0].Name != "Types" || layers[2].Name != "Relationships" {
+		t.Errorf("unexpected layer order: %s, %s, %s", layers[0].Name, layers[1].Name, layers[2].Name)
+	}
+}
+
+func TestExpandedEntitiesAreLayered(t *testing.T) {
+	ds, err := LoadEmbeddedDataset("construction")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.expand(NewGenerator(DEFAULT_GENERATOR_SEED)); err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for i, layer := range ds.layers() {
+		for _, entity := range layer.Entities {
+			if dmLayerIndex(entity.Kind) != i {
+				t.Errorf("%s '%s' applied in layer %s", entity.Kind.Title, entity.Token(), layer.Name)
+			}
+			count++
+		}
+	}
+	if count != len(ds.Entities) {
+		t.Errorf("layers hold %d of %d entities", count, len(ds.Entities))
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// Seed used unless another seed or a random seed is requested, so that repeated runs
+	// generate the same entities.
+	DEFAULT_GENERATOR_SEED = 1
+)
+
+var (
+	// Date that generated dates are relative to, so that they do not change between runs.
+	GENERATOR_REFERENCE_DATE = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Runes available for generating vehicle identifiers.
+var vinRunes = []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+
+// Source of generated values used by dataset templates. Generators created with
+// the same seed produce the same sequence of values.
+type Generator interface {
+	// Seed used to create the generator.
+	Seed() int64
+	// Random vehicle identifier of the given length.
+	Vin(n int) string
+	// Random integer in the range [min, max].
+	Int(min int, max int) int
+	// Random item from the given choices.
+	Pick(choices ...interface{}) (interface{}, error)
+	// Reference date formatted with the given layout.
+	Date(layout string) string
+	// Random date up to the given number of days before the reference date.
+	DateWithin(layout string, days int) string
+}
+
+// Generator backed by a seeded pseudo-random source.
+type seededGenerator struct {
+	seed int64
+	rand *rand.Rand
+}
+
+// Create a generator with the given seed.
+func NewGenerator(seed int64) Generator {
+	return &seededGenerator{seed: seed, rand: rand.New(rand.NewSource(seed))}
+}
+
+func (g *seededGenerator) Seed() int64 {
+	return g.seed
+}
+
+func (g *seededGenerator) Vin(n int) string {
+	b := make([]rune, n)
+	for i := range b {
+		b[i] = vinRunes[g.rand.Intn(len(vinRunes))]
+	}
+	return string(b)
+}
+
+func (g *seededGenerator) Int(min int, max int) int {
+	if max <= min {
+		return min
+	}
+	return min + g.rand.Intn(max-min+1)
+}
+
+func (g *seededGenerator) Pick(choices ...interface{}) (interface{}, error) {
+	if len(choices) == 0 {
+		return nil, errors.New("pick requires at least one choice")
+	}
+	return choices[g.rand.Intn(len(choices))], nil
+}
+
+func (g *seededGenerator) Date(layout string) string {
+	return GENERATOR_REFERENCE_DATE.Format(layout)
+}
+
+func (g *seededGenerator) DateWithin(layout string, days int) string {
+	return GENERATOR_REFERENCE_DATE.AddDate(0, 0, -g.Int(0, days)).Format(layout)
+}
+
+// Create a generator based on the seed flags. If a random seed is requested, the chosen seed
+// is reported so that the run may be reproduced.
+func newGeneratorForCommand(cmd *cobra.Command) (Generator, error) {
+	seed, _ := cmd.Flags().GetInt64("seed")
+	random, _ := cmd.Flags().GetBool("random-seed")
+	if random {
+		if cmd.Flags().Changed("seed") {
+			return nil, errors.New("seed and random-seed can not be used together")
+		}
+		seed = time.Now().UnixNano()
+		fmt.Fprintf(output.Progress(), "Using random generator seed %d (pass --seed %d to reproduce).\n", seed, seed)
+	}
+	return NewGenerator(seed), nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// Draw a sequence of generated values.
+func generatedValues(gen Generator) []string {
+	values := make([]string, 0)
+	for i := 0; i < 10; i++ {
+		values = append(values, gen.Vin(17), gen.DateWithin("2006-01-02", 365))
+	}
+	return values
+}
+
+func TestNewGeneratorIsReproducible(t *testing.T) {
+	for _, seed := range []int64{0, DEFAULT_GENERATOR_SEED, 42, -7} {
+		first := generatedValues(NewGenerator(seed))
+		second := generatedValues(NewGenerator(seed))
+		for i := range first {
+			if first[i] != second[i] {
+				t.Fatalf("seed %d: value %d differs between generators (%s != %s)", seed, i, first[i], second[i])
+			}
+		}
+	}
+}
+
+func TestNewGeneratorSeedsDiffer(t *testing.T) {
+	first := generatedValues(NewGenerator(1))
+	second := generatedValues(NewGenerator(2))
+	if first[0] == second[0] && first[2] == second[2] {
+		t.Errorf("generators with different seeds produced the same VINs (%s, %s)", first[0], first[2])
+	}
+}
+
+func TestGeneratorValues(t *testing.T) {
+	gen := NewGenerator(DEFAULT_GENERATOR_SEED)
+	if vin := gen.Vin(17); len(vin) != 17 {
+		t.Errorf("vin %s has length %d", vin, len(vin))
+	}
+	if date := gen.Date("2006-01-02"); date != "2022-01-01" {
+		t.Errorf("date = %s, want reference date", date)
+	}
+	for i := 0; i < 100; i++ {
+		if n := gen.Int(3, 5); n < 3 || n > 5 {
+			t.Fatalf("int %d out of range [3, 5]", n)
+		}
+	}
+	if _, err := gen.Pick(); err == nil {
+		t.Error("expected error picking from no choices")
+	}
+}
+
+func TestNewGeneratorForCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantSeed int64
+		random   bool
+		wantErr  bool
+	}{
+		{name: "default seed", wantSeed: DEFAULT_GENERATOR_SEED},
+		{name: "explicit seed", args: []string{"--seed", "42"}, wantSeed: 42},
+		{name: "random seed", args: []string{"--random-seed"}, random: true},
+		{name: "conflicting seeds", args: []string{"--seed", "42", "--random-seed"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().Int64("seed", DEFAULT_GENERATOR_SEED, "")
+			cmd.Flags().Bool("random-seed", false, "")
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			gen, err := newGeneratorForCommand(cmd)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.random && gen.Seed() != tt.wantSeed {
+				t.Errorf("seed = %d, want %d", gen.Seed(), tt.wantSeed)
+			}
+			if tt.random && gen.Seed() == DEFAULT_GENERATOR_SEED {
+				t.Error("random seed matches default seed")
+			}
+		})
+	}
+}
+
+// Expand the construction dataset and render its entities for comparison.
+func expandedConstruction(t *testing.T, seed int64) string {
+	ds, err := LoadEmbeddedDataset("construction")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.expand(NewGenerator(seed)); err != nil {
+		t.Fatal(err)
+	}
+	fields := make([]map[string]interface{}, 0, len(ds.Entities))
+	for _, entity := range ds.Entities {
+		fields = append(fields, entity.Fields)
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestExpandedDatasetIsReproducible(t *testing.T) {
+	if expandedConstruction(t, DEFAULT_GENERATOR_SEED) != expandedConstruction(t, DEFAULT_GENERATOR_SEED) {
+		t.Error("expanding the construction dataset twice with the same seed produced different entities")
+	}
+	if expandedConstruction(t, DEFAULT_GENERATOR_SEED) == expandedConstruction(t, 42) {
+		t.Error("expanding the construction dataset with different seeds produced the same entities")
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	UNSAFE_PATH_CHARS = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// Entity created by a bootstrap run.
+type ManifestEntry struct {
+	Kind  string `json:"kind"`
+	Token string `json:"token"`
+}
+
+// Record of entities created by bootstrap runs of a dataset against a tenant.
+type BootstrapManifest struct {
+	Dataset  string          `json:"dataset"`
+	Server   string          `json:"server"`
+	Instance string          `json:"instance"`
+	Tenant   string          `json:"tenant"`
+	Updated  time.Time       `json:"updated"`
+	Entities []ManifestEntry `json:"entities"`
+	mu       sync.Mutex
+	path     string
+}
+
+// Get path of the manifest for a dataset on the targeted tenant. Dataset names are normalized
+// so that a title such as "Smart Building" maps to the same manifest as "smart-building".
+func manifestPath(cmd *cobra.Command, dataset string) (string, string, string, string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", "", "", "", err
+	}
+	server := config.Server.Resolve(cmd.Flags())
+	instance := config.Instance.Resolve(cmd.Flags())
+	tenant := config.Tenant.Resolve(cmd.Flags())
+	target := UNSAFE_PATH_CHARS.ReplaceAllString(fmt.Sprintf("%s_%s_%s", server, instance, tenant), "_")
+	name := UNSAFE_PATH_CHARS.ReplaceAllString(strings.ToLower(dataset), "-")
+	return filepath.Join(dir, "bootstrap", target, name+".json"), server, instance, tenant, nil
+}
+
+// Load the manifest for a dataset on the targeted tenant. An empty manifest is returned if none exists.
+func LoadBootstrapManifest(cmd *cobra.Command, dataset string) (*BootstrapManifest, error) {
+	path, server, instance, tenant, err := manifestPath(cmd, dataset)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &BootstrapManifest{
+		Dataset:  dataset,
+		Server:   server,
+		Instance: instance,
+		Tenant:   tenant,
+		Entities: make([]ManifestEntry, 0),
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		manifest.path = path
+		return manifest, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, manifest); err != nil {
+		return nil, fmt.Errorf("unable to parse bootstrap manifest '%s': %v", path, err)
+	}
+	manifest.path = path
+	return manifest, nil
+}
+
+// Record an entity created by a bootstrap run.
+func (m *BootstrapManifest) Add(kind *dmKind, token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range m.Entities {
+		if entry.Kind == kind.Name && entry.Token == token {
+			return
+		}
+	}
+	m.Entities = append(m.Entities, ManifestEntry{Kind: kind.Name, Token: token})
+}
+
+// Remove an entity from the manifest.
+func (m *BootstrapManifest) Remove(entry ManifestEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.Entities {
+		if existing == entry {
+			m.Entities = append(m.Entities[:i], m.Entities[i+1:]...)
+			return
+		}
+	}
+}
+
+// Save the manifest. The manifest file is removed once no entities remain.
+func (m *BootstrapManifest) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.Entities) == 0 {
+		err := os.Remove(m.path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	m.Updated = time.Now()
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(m.path, b, 0600)
+}
+
+// Get index of the apply layer for a kind.
+func dmLayerIndex(kind *dmKind) int {
+	for i, layer := range dmLayerRoles {
+		for _, role := range layer.roles {
+			if kind.Role == role {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// Get entries in reverse dependency order (relationships first, types last). Entries
+// in the same layer are returned in reverse order of creation.
+func (m *BootstrapManifest) TeardownOrder() ([]ManifestEntry, error) {
+	entries := make([]ManifestEntry, 0, len(m.Entities))
+	layers := make(map[ManifestEntry]int)
+	for i := len(m.Entities) - 1; i >= 0; i-- {
+		entry := m.Entities[i]
+		kind, err := findDmKind(entry.Kind)
+		if err != nil {
+			return nil, err
+		}
+		layers[entry] = dmLayerIndex(kind)
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return layers[entries[i]] > layers[entries[j]]
+	})
+	return entries, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Build manifest entries from kind and token pairs.
+func manifestEntries(pairs ...string) []ManifestEntry {
+	entries := make([]ManifestEntry, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		entries = append(entries, ManifestEntry{Kind: pairs[i], Token: pairs[i+1]})
+	}
+	return entries
+}
+
+func TestTeardownOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		created []ManifestEntry
+		want    []ManifestEntry
+	}{
+		{
+			name:    "empty",
+			created: manifestEntries(),
+			want:    manifestEntries(),
+		},
+		{
+			name: "reverse dependency order",
+			created: manifestEntries(
+				"device-types", "sensor",
+				"device-relationship-types", "tracks",
+				"devices", "d1",
+				"devices", "d2",
+				"device-relationships", "d1-tracks-d2"),
+			want: manifestEntries(
+				"device-relationships", "d1-tracks-d2",
+				"devices", "d2",
+				"devices", "d1",
+				"device-relationship-types", "tracks",
+				"device-types", "sensor"),
+		},
+		{
+			// Entities recorded by a later run are still deleted before types from an earlier run.
+			name: "interleaved runs",
+			created: manifestEntries(
+				"device-types", "sensor",
+				"devices", "d1",
+				"device-group-relationships", "group-contains-d1",
+				"asset-types", "truck",
+				"device-groups", "group",
+				"assets", "a1"),
+			want: manifestEntries(
+				"device-group-relationships", "group-contains-d1",
+				"assets", "a1",
+				"device-groups", "group",
+				"devices", "d1",
+				"asset-types", "truck",
+				"device-types", "sensor"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := &BootstrapManifest{Entities: tt.created}
+			entries, err := manifest.TeardownOrder()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("teardown order = %v, want %v", entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestTeardownOrderUnknownKind(t *testing.T) {
+	manifest := &BootstrapManifest{Entities: manifestEntries("devices", "d1", "widgets", "w1")}
+	if _, err := manifest.TeardownOrder(); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+}
+
+func TestManifestAddAndRemove(t *testing.T) {
+	devices, err := findDmKind("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := &BootstrapManifest{Entities: make([]ManifestEntry, 0)}
+	manifest.Add(devices, "d1")
+	manifest.Add(devices, "d2")
+	manifest.Add(devices, "d1")
+	if want := manifestEntries("devices", "d1", "devices", "d2"); !reflect.DeepEqual(manifest.Entities, want) {
+		t.Errorf("entities = %v, want %v", manifest.Entities, want)
+	}
+	manifest.Remove(ManifestEntry{Kind: "devices", Token: "d1"})
+	if want := manifestEntries("devices", "d2"); !reflect.DeepEqual(manifest.Entities, want) {
+		t.Errorf("entities = %v, want %v", manifest.Entities, want)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Intialize command for removing bootstrapped data
+var bootstrapResetCmd = NewBootstrapResetCommand()
+
+// Create command that will remove entities created by bootstrapping a dataset
+func NewBootstrapResetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset dataset",
+		Short: "Remove data created by bootstrap",
+		Long: `Deletes entities recorded in the manifest of bootstrap runs for a dataset in reverse
+dependency order. Entities that already existed before bootstrap are not removed.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			return resetDataset(cmd, args, dryRun)
+		}}
+	cmd.Flags().Bool("dry-run", false, "list entities that would be removed without deleting them")
+	return cmd
+}
+
+// Delete entities created by bootstrapping a dataset
+func resetDataset(cmd *cobra.Command, args []string, dryRun bool) error {
+	if len(args) < 1 {
+		return errors.New("no dataset passed to reset")
+	}
+	manifest, err := LoadBootstrapManifest(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	if len(manifest.Entities) == 0 {
+		return fmt.Errorf("no bootstrap manifest found for dataset '%s' on %s/%s/%s", args[0],
+			manifest.Server, manifest.Instance, manifest.Tenant)
+	}
+	entries, err := manifest.TeardownOrder()
+	if err != nil {
+		return err
+	}
+
+	report := NewOperationReport("reset")
+	if dryRun {
+		for _, entry := range entries {
+			kind, _ := findDmKind(entry.Kind)
+			report.Step(kind.Title, entry.Token, "would delete")
+			fmt.Fprintf(output.Progress(), "Would delete %s '%s'.\n", kind.Title, entry.Token)
+		}
+		return report.Complete()
+	}
+
+	continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+	dm, err := gql.NewDeviceManagementGraphQLClient(cmd)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	failed := 0
+	for _, entry := range entries {
+		kind, _ := findDmKind(entry.Kind)
+		_, err := kind.delete(ctx, &dm, entry.Token)
+		if err != nil {
+			failed++
+			report.Step(kind.Title, entry.Token, "failed")
+			fmt.Fprintf(output.Progress(), color.HiRedString("Unable to delete %s '%s': %v\n"), kind.Title, entry.Token, err)
+			if !continueOnError {
+				break
+			}
+			continue
+		}
+		manifest.Remove(entry)
+		report.Step(kind.Title, entry.Token, "deleted")
+		fmt.Fprintf(output.Progress(), color.HiGreenString("Deleted %s '%s'.\n"), kind.Title, entry.Token)
+	}
+
+	// Keep entries that could not be deleted so reset may be retried.
+	if err := manifest.Save(); err != nil {
+		return err
+	}
+	if err := report.Complete(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("reset of %s dataset failed for %d entities", args[0], failed)
+	}
+	return nil
+}
+
+func init() {
+	bootstrapCmd.AddCommand(bootstrapResetCmd)
+}
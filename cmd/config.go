@@ -0,0 +1,42 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/spf13/cobra"
+)
+
+// Create common command for managing dcctl configuration
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage dcctl configuration",
+	Long:  `Manages named contexts stored in the dcctl configuration file`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Configuration commands never talk to Kubernetes.
+		return configureOutput()
+	},
+}
+
+// Load configuration, failing if the named context does not exist.
+func getConfigWithContext(name string) (*config.Config, *config.Context, error) {
+	cfg, err := config.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, ok := cfg.Contexts[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("context '%s' does not exist", name)
+	}
+	return cfg, ctx, nil
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
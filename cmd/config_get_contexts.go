@@ -0,0 +1,81 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/spf13/cobra"
+)
+
+// Intialize command for listing contexts
+var configGetContextsCmd = NewConfigGetContextsCommand()
+
+// Create command that will list contexts
+func NewConfigGetContextsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "get-contexts",
+		Short:        "List configured contexts",
+		Long:         `Lists all contexts in the dcctl configuration file`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getContexts()
+		}}
+}
+
+// Summary of a configured context.
+type ContextSummary struct {
+	Name        string `json:"name"`
+	Current     bool   `json:"current"`
+	Server      string `json:"server,omitempty"`
+	Instance    string `json:"instance,omitempty"`
+	Tenant      string `json:"tenant,omitempty"`
+	KubeContext string `json:"kubeContext,omitempty"`
+}
+
+// List of context summaries.
+type ContextSummaries []ContextSummary
+
+// Render contexts as a table.
+func (list ContextSummaries) Table() *output.Table {
+	table := output.NewTable(output.Column{Header: "CURRENT"}, output.Column{Header: "NAME"},
+		output.Column{Header: "SERVER"}, output.Column{Header: "INSTANCE"}, output.Column{Header: "TENANT"},
+		output.Column{Header: "KUBE CONTEXT"})
+	for _, ctx := range list {
+		current := ""
+		if ctx.Current {
+			current = "*"
+		}
+		table.AddRow(current, ctx.Name, ctx.Server, ctx.Instance, ctx.Tenant, ctx.KubeContext)
+	}
+	return table
+}
+
+// List all contexts
+func getContexts() error {
+	cfg, err := config.Get()
+	if err != nil {
+		return err
+	}
+	list := make(ContextSummaries, 0)
+	for _, name := range cfg.ContextNames() {
+		ctx := cfg.Contexts[name]
+		list = append(list, ContextSummary{
+			Name:        name,
+			Current:     name == cfg.CurrentContext,
+			Server:      ctx.Server,
+			Instance:    ctx.Instance,
+			Tenant:      ctx.Tenant,
+			KubeContext: ctx.KubeContext,
+		})
+	}
+	return output.Print(list)
+}
+
+func init() {
+	configCmd.AddCommand(configGetContextsCmd)
+}
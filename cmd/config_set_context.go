@@ -0,0 +1,78 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Intialize command for creating or modifying a context
+var configSetContextCmd = NewConfigSetContextCommand()
+
+// Create command that will create or modify a context
+func NewConfigSetContextCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "set-context context_name",
+		Short:        "Create or modify a context",
+		Long:         `Creates a context or modifies settings on an existing context. Only flags that are passed are changed.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			current, _ := cmd.Flags().GetBool("current")
+			return setContext(cmd, args, current)
+		}}
+	for _, setting := range config.Settings {
+		cmd.Flags().String(setting.Name, "", fmt.Sprintf("value of '%s' for context", setting.Name))
+	}
+	cmd.Flags().Bool("current", false, "also make this the current context")
+	return cmd
+}
+
+// Create or modify a context
+func setContext(cmd *cobra.Command, args []string, current bool) error {
+	if len(args) < 1 {
+		return errors.New("no context name passed")
+	}
+	cfg, err := config.Get()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	ctx, exists := cfg.Contexts[name]
+	if !exists {
+		ctx = &config.Context{}
+		cfg.Contexts[name] = ctx
+	}
+	for _, setting := range config.Settings {
+		flag := cmd.Flags().Lookup(setting.Name)
+		if flag.Changed {
+			setting.Set(ctx, flag.Value.String())
+		}
+	}
+	if current || cfg.CurrentContext == "" {
+		cfg.CurrentContext = name
+	}
+	err = config.Save(cfg)
+	if err != nil {
+		return err
+	}
+	if exists {
+		fmt.Fprintf(output.Progress(), color.HiGreenString("Modified context '%s'.\n"), name)
+	} else {
+		fmt.Fprintf(output.Progress(), color.HiGreenString("Created context '%s'.\n"), name)
+	}
+	return nil
+}
+
+func init() {
+	configCmd.AddCommand(configSetContextCmd)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Intialize command for switching the current context
+var configUseContextCmd = NewConfigUseContextCommand()
+
+// Create command that will switch the current context
+func NewConfigUseContextCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "use-context context_name",
+		Short:        "Set the current context",
+		Long:         `Sets the context used to resolve settings for subsequent commands`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return useContext(args)
+		}}
+}
+
+// Switch the current context
+func useContext(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no context name passed")
+	}
+	cfg, _, err := getConfigWithContext(args[0])
+	if err != nil {
+		return err
+	}
+	cfg.CurrentContext = args[0]
+	err = config.Save(cfg)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output.Progress(), color.HiGreenString("Switched to context '%s'.\n"), args[0])
+	return nil
+}
+
+func init() {
+	configCmd.AddCommand(configUseContextCmd)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/spf13/cobra"
+)
+
+const REDACTED = "REDACTED"
+
+// Intialize command for viewing configuration
+var configViewCmd = NewConfigViewCommand()
+
+// Create command that will display configuration
+func NewConfigViewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "view",
+		Short:        "Display configuration",
+		Long:         `Displays the dcctl configuration file with credentials redacted`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			raw, _ := cmd.Flags().GetBool("raw")
+			return viewConfig(raw)
+		}}
+	cmd.Flags().Bool("raw", false, "display credentials without redaction")
+	return cmd
+}
+
+// Display configuration
+func viewConfig(raw bool) error {
+	cfg, err := config.Get()
+	if err != nil {
+		return err
+	}
+	view := &config.Config{CurrentContext: cfg.CurrentContext, Contexts: make(map[string]*config.Context)}
+	for name, ctx := range cfg.Contexts {
+		redacted := *ctx
+		if !raw && redacted.Password != "" {
+			redacted.Password = REDACTED
+		}
+		view.Contexts[name] = &redacted
+	}
+	if output.GetFormat() == output.FormatText {
+		fmt.Printf("# %s\n", config.GetPath())
+	}
+	return output.Print(view)
+}
+
+func init() {
+	configCmd.AddCommand(configViewCmd)
+}
@@ -14,7 +14,7 @@ import (
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create system resources",
-	Long:  `Commands that create DeviceChain system resources`,
+	Long:  `Commands that create DeviceChain system resources and device management entities`,
 }
 
 func init() {
@@ -0,0 +1,22 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Create common command for deleting DeviceChain resources
+var deleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete system resources",
+	Long:  `Commands that delete DeviceChain system resources and device management entities`,
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+}
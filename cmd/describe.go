@@ -0,0 +1,22 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Create common command for describing DeviceChain resources
+var describeCmd = &cobra.Command{
+	Use:   "describe",
+	Short: "Show details of DeviceChain resources",
+	Long:  `Shows spec, status conditions and related resources for DeviceChain Kubernetes resources`,
+}
+
+func init() {
+	rootCmd.AddCommand(requireKubernetes(describeCmd))
+}
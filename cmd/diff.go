@@ -0,0 +1,22 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Create common command for comparing DeviceChain components with a cluster
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare system components with a cluster",
+	Long:  `Shows differences between the components dcctl would install and the live cluster`,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,182 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// Create instance of diff core command
+var diffCoreCmd = NewDiffCoreCommand()
+
+// Create command for comparing DeviceChain core resources with the cluster
+func NewDiffCoreCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "core",
+		Short: "Compare core components with the cluster",
+		Long: `Renders a unified diff between the manifests installed by 'install core' (CRDs, RBAC,
+operator and generated resources) and the live objects in the cluster. By default each
+manifest is compared after a server-side dry run so that fields defaulted by the server
+are not reported. The command exits with an error if any object differs.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server, _ := cmd.Flags().GetBool("server-side")
+			return diffCore(server)
+		}}
+	cmd.Flags().Bool("server-side", true, "compare against the result of a server-side dry run of each manifest")
+	return cmd
+}
+
+// Difference between a manifest object and the live object.
+type ManifestDiff struct {
+	Group  string `json:"group"`
+	Source string `json:"source"`
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+// Differences between manifests and the live cluster.
+type ManifestDiffList struct {
+	Items []*ManifestDiff `json:"items"`
+}
+
+// Render differences as a table.
+func (list *ManifestDiffList) Table() *output.Table {
+	table := output.NewTable(output.Column{Header: "GROUP"}, output.Column{Header: "KIND"},
+		output.Column{Header: "NAME"}, output.Column{Header: "STATUS"}, output.Column{Header: "SOURCE", Wide: true})
+	for _, item := range list.Items {
+		table.AddRow(item.Group, item.Kind, item.Name, item.Status, item.Source)
+	}
+	return table
+}
+
+// Compare a manifest object with the live object.
+func diffManifest(ctx context.Context, mc *manifestClient, manifest *coreManifest, server bool) (*ManifestDiff, error) {
+	result := &ManifestDiff{Group: manifest.Group, Source: manifest.Source, Kind: manifest.Object.GetKind(),
+		Name: manifest.Object.GetName()}
+	if ns := manifest.Object.GetNamespace(); ns != "" {
+		result.Name = ns + "/" + result.Name
+	}
+	live, err := mc.live(ctx, manifest.Object)
+	if err != nil && !meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("unable to get %s: %v", manifest, err)
+	}
+	desired, err := mc.desired(ctx, manifest.Object, server)
+	if err != nil {
+		return nil, fmt.Errorf("dry run failed for %s from '%s': %v", manifest, manifest.Source, err)
+	}
+
+	before, err := normalizedYaml(live)
+	if err != nil {
+		return nil, err
+	}
+	after, err := normalizedYaml(desired)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case before == after:
+		result.Status = "unchanged"
+		return result, nil
+	case live == nil:
+		result.Status = "missing"
+	default:
+		result.Status = "changed"
+	}
+
+	path := fmt.Sprintf("%s/%s", strings.ToLower(result.Kind), result.Name)
+	result.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(before),
+		B:        diffLines(after),
+		FromFile: "live/" + path,
+		ToFile:   "embedded/" + path,
+		Context:  3,
+	})
+	return result, err
+}
+
+// Split text into lines for diffing. Empty text has no lines.
+func diffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return difflib.SplitLines(text)
+}
+
+// Print a unified diff with added and removed lines colored.
+func printDiff(diff string) {
+	for _, line := range difflib.SplitLines(diff) {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			fmt.Print(color.HiWhiteString(line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Print(color.GreenString(line))
+		case strings.HasPrefix(line, "-"):
+			fmt.Print(color.RedString(line))
+		case strings.HasPrefix(line, "@@"):
+			fmt.Print(color.CyanString(line))
+		default:
+			fmt.Print(line)
+		}
+	}
+}
+
+// Compare core manifests with live cluster objects
+func diffCore(server bool) error {
+	manifests, err := loadCoreManifests()
+	if err != nil {
+		return err
+	}
+	dynamicClient, discoveryClient, err := createClients()
+	if err != nil {
+		return err
+	}
+	mc := newManifestClient(dynamicClient, discoveryClient)
+	ctx := context.Background()
+
+	list := &ManifestDiffList{Items: make([]*ManifestDiff, 0, len(manifests))}
+	changed := 0
+	for _, manifest := range manifests {
+		result, err := diffManifest(ctx, mc, manifest, server)
+		if err != nil {
+			return err
+		}
+		list.Items = append(list.Items, result)
+		if result.Diff == "" {
+			continue
+		}
+		changed++
+		if output.GetFormat() == output.FormatText {
+			printDiff(result.Diff)
+		}
+	}
+	if output.GetFormat() != output.FormatText {
+		if err := output.Print(list); err != nil {
+			return err
+		}
+	}
+
+	if changed == 0 {
+		fmt.Fprintln(output.Progress(), color.HiGreenString("All %d objects match the embedded manifests.", len(list.Items)))
+		return nil
+	}
+	return fmt.Errorf("%d of %d objects differ from the embedded manifests", changed, len(list.Items))
+}
+
+func init() {
+	diffCmd.AddCommand(requireKubernetes(diffCoreCmd))
+}
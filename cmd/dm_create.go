@@ -0,0 +1,301 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	FIELD_WORD_BOUNDARY = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+)
+
+// Request fields that are not exposed as generic flags.
+var specialRequestFields = map[string]bool{"Token": true, "Metadata": true}
+
+// Convert a request field name to a flag name (e.g. DeviceTypeToken to device-type-token).
+func fieldFlagName(field string) string {
+	return strings.ToLower(FIELD_WORD_BOUNDARY.ReplaceAllString(field, "$1-$2"))
+}
+
+// Request field that may be set from a flag.
+type requestField struct {
+	Flag  string
+	Index []int
+	Kind  reflect.Kind
+	Ptr   bool
+}
+
+// Find fields of a request struct that may be set from flags. Nested structs are flattened.
+func requestFields(t reflect.Type, index []int) []requestField {
+	fields := make([]requestField, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || specialRequestFields[field.Name] {
+			continue
+		}
+		path := append(append([]int{}, index...), i)
+		ftype := field.Type
+		ptr := ftype.Kind() == reflect.Ptr
+		if ptr {
+			ftype = ftype.Elem()
+		}
+		switch ftype.Kind() {
+		case reflect.Struct:
+			if !ptr {
+				fields = append(fields, requestFields(ftype, path)...)
+			}
+		case reflect.String, reflect.Bool:
+			fields = append(fields, requestField{Flag: fieldFlagName(field.Name), Index: path, Kind: ftype.Kind(), Ptr: ptr})
+		}
+	}
+	return fields
+}
+
+// Add flags for each settable field of a request.
+func addRequestFlags(flags *pflag.FlagSet, kind *dmKind) {
+	for _, field := range requestFields(reflect.TypeOf(kind.newRequest()).Elem(), nil) {
+		usage := fmt.Sprintf("%s of %s", strings.ReplaceAll(field.Flag, "-", " "), kind.Title)
+		if field.Kind == reflect.Bool {
+			flags.Bool(field.Flag, false, usage)
+		} else {
+			flags.String(field.Flag, "", usage)
+		}
+	}
+	flags.String("metadata", "", fmt.Sprintf("metadata of %s as a JSON object", kind.Title))
+	flags.String("metadata-file", "", fmt.Sprintf("path to JSON or YAML file with metadata of %s", kind.Title))
+}
+
+// Set request fields for each flag that was passed.
+func applyRequestFlags(flags *pflag.FlagSet, request interface{}) error {
+	value := reflect.ValueOf(request).Elem()
+	for _, field := range requestFields(value.Type(), nil) {
+		flag := flags.Lookup(field.Flag)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		target := value.FieldByIndex(field.Index)
+		var fvalue reflect.Value
+		if field.Kind == reflect.Bool {
+			b, _ := flags.GetBool(field.Flag)
+			fvalue = reflect.ValueOf(b)
+		} else {
+			fvalue = reflect.ValueOf(flag.Value.String())
+		}
+		if field.Ptr {
+			ptr := reflect.New(fvalue.Type())
+			ptr.Elem().Set(fvalue)
+			fvalue = ptr
+		}
+		target.Set(fvalue)
+	}
+
+	metadata, err := getMetadataFromFlags(flags)
+	if err != nil {
+		return err
+	}
+	if metadata != nil {
+		if field := value.FieldByName("Metadata"); field.IsValid() {
+			field.Set(reflect.ValueOf(metadata))
+		}
+	}
+	return nil
+}
+
+// Check that all required (non-pointer string) fields of a request are set.
+func checkRequiredFields(request interface{}, kind *dmKind) error {
+	value := reflect.ValueOf(request).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" || field.Type.Kind() != reflect.String || field.Name == "Token" {
+			continue
+		}
+		if value.Field(i).String() == "" {
+			return fmt.Errorf("no %s passed for %s", fieldFlagName(field.Name), kind.Title)
+		}
+	}
+	if targets := value.FieldByName("Targets"); targets.IsValid() && targets.Kind() == reflect.Struct {
+		return checkSingleTarget(targets, kind)
+	}
+	return nil
+}
+
+// Check that exactly one target of a relationship is set.
+func checkSingleTarget(targets reflect.Value, kind *dmKind) error {
+	options := make([]string, 0)
+	passed := make([]string, 0)
+	for i := 0; i < targets.NumField(); i++ {
+		field := targets.Type().Field(i)
+		if field.PkgPath != "" || field.Type.Kind() != reflect.Ptr || field.Type.Elem().Kind() != reflect.String {
+			continue
+		}
+		flag := "--" + fieldFlagName(field.Name)
+		options = append(options, flag)
+		if value := targets.Field(i); !value.IsNil() && value.Elem().String() != "" {
+			passed = append(passed, flag)
+		}
+	}
+	switch len(passed) {
+	case 1:
+		return nil
+	case 0:
+		return fmt.Errorf("no target passed for %s (expected one of %s)", kind.Title, strings.Join(options, ", "))
+	default:
+		return fmt.Errorf("only one target may be passed for %s (got %s)", kind.Title, strings.Join(passed, ", "))
+	}
+}
+
+// Read a file as JSON. YAML is converted to JSON. A path of '-' reads from stdin.
+func readJsonOrYamlFile(path string) ([]byte, error) {
+	var b []byte
+	var err error
+	if path == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b, err = yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse '%s': %v", path, err)
+	}
+	return b, nil
+}
+
+// Get metadata passed as a flag or loaded from a file.
+func getMetadataFromFlags(flags *pflag.FlagSet) (*string, error) {
+	metadata, _ := flags.GetString("metadata")
+	path, _ := flags.GetString("metadata-file")
+	if metadata != "" && path != "" {
+		return nil, errors.New("metadata and metadata-file may not be used together")
+	}
+	if path != "" {
+		b, err := readJsonOrYamlFile(path)
+		if err != nil {
+			return nil, err
+		}
+		metadata = string(b)
+	}
+	if metadata == "" {
+		return nil, nil
+	}
+	if !json.Valid([]byte(metadata)) {
+		return nil, errors.New("metadata is not valid JSON")
+	}
+	return &metadata, nil
+}
+
+// Load a request from a JSON or YAML file.
+func loadRequestFile(path string, request interface{}) error {
+	b, err := readJsonOrYamlFile(path)
+	if err != nil {
+		return err
+	}
+	err = decodeRequest(b, request)
+	if err != nil {
+		return fmt.Errorf("'%s' must contain a single object: %v", path, err)
+	}
+	return nil
+}
+
+// Decode a request from a JSON object. Metadata may be given as an object.
+func decodeRequest(b []byte, request interface{}) error {
+	fields := make(map[string]interface{})
+	err := json.Unmarshal(b, &fields)
+	if err != nil {
+		return err
+	}
+	if metadata, ok := fields["metadata"]; ok && metadata != nil {
+		if _, isString := metadata.(string); !isString {
+			encoded, err := json.Marshal(metadata)
+			if err != nil {
+				return err
+			}
+			fields["metadata"] = string(encoded)
+		}
+	}
+	b, err = json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, request)
+}
+
+// Create command that will create an entity of a device management kind
+func newCreateDmKindCommand(kind *dmKind) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          fmt.Sprintf("%s [token]", kind.Singular),
+		Aliases:      []string{kind.Name},
+		Short:        fmt.Sprintf("Create a new %s", kind.Title),
+		Long:         fmt.Sprintf(`Creates a new %s from flags or a JSON/YAML file. Flags override values from the file.`, kind.Title),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return createDmEntity(cmd, kind, args)
+		}}
+	addTargetFlags(cmd.Flags())
+	cmd.Flags().StringP("filename", "f", "", "JSON or YAML file containing the entity ('-' for stdin)")
+	addRequestFlags(cmd.Flags(), kind)
+	return cmd
+}
+
+// Create a device management entity
+func createDmEntity(cmd *cobra.Command, kind *dmKind, args []string) error {
+	request := kind.newRequest()
+	filename, _ := cmd.Flags().GetString("filename")
+	if filename != "" {
+		if err := loadRequestFile(filename, request); err != nil {
+			return err
+		}
+	}
+	err := applyRequestFlags(cmd.Flags(), request)
+	if err != nil {
+		return err
+	}
+	token := reflect.ValueOf(request).Elem().FieldByName("Token")
+	if len(args) > 0 {
+		token.SetString(args[0])
+	}
+	if token.String() == "" {
+		return fmt.Errorf("no token passed for new %s", kind.Title)
+	}
+	err = checkRequiredFields(request, kind)
+	if err != nil {
+		return err
+	}
+
+	dm, err := gql.NewDeviceManagementGraphQLClient(cmd)
+	if err != nil {
+		return err
+	}
+	created, _, err := kind.create(context.Background(), &dm, request)
+	if err != nil {
+		return err
+	}
+	return output.PrintResult(created)
+}
+
+func init() {
+	for _, kind := range dmKinds {
+		createCmd.AddCommand(newCreateDmKindCommand(kind))
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	dmmodel "github.com/devicechain-io/dc-device-management/model"
+)
+
+func TestCheckRequiredFieldsForRelationships(t *testing.T) {
+	kind, err := findDmKind("device-relationships")
+	if err != nil {
+		t.Fatal(err)
+	}
+	device, asset, empty := "truck-1", "trailer-1", ""
+	tests := []struct {
+		name    string
+		targets dmmodel.EntityRelationshipCreateRequest
+		wantErr string
+	}{
+		{name: "single target", targets: dmmodel.EntityRelationshipCreateRequest{TargetDevice: &device}},
+		{name: "no target", wantErr: "no target passed"},
+		{name: "empty target", targets: dmmodel.EntityRelationshipCreateRequest{TargetAsset: &empty}, wantErr: "no target passed"},
+		{name: "two targets", targets: dmmodel.EntityRelationshipCreateRequest{TargetDevice: &device, TargetAsset: &asset},
+			wantErr: "--target-device, --target-asset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &dmmodel.DeviceRelationshipCreateRequest{
+				Token:            "rel-1",
+				SourceDevice:     "tracker-1",
+				RelationshipType: "attached",
+				Targets:          tt.targets,
+			}
+			err := checkRequiredFields(request, kind)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckRequiredFieldsWithoutTargets(t *testing.T) {
+	kind, err := findDmKind("device-relationship-types")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkRequiredFields(&dmmodel.DeviceRelationshipTypeCreateRequest{Token: "attached"}, kind); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuiltinDatasetsHaveSingleTargets(t *testing.T) {
+	names, err := BuiltinDatasetNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			ds, err := LoadEmbeddedDataset(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ds.expand(NewGenerator(DEFAULT_GENERATOR_SEED)); err != nil {
+				t.Fatal(err)
+			}
+			if err := ds.prepare(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
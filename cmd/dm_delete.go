@@ -0,0 +1,215 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// Page size used when loading entities to check references.
+	REFERENCE_PAGE_SIZE = 500
+
+	// Maximum number of references listed in error messages.
+	MAX_LISTED_REFERENCES = 5
+)
+
+// Entity identified by kind and token.
+type dmEntityRef struct {
+	kind  *dmKind
+	token string
+}
+
+// Deletes entities, checking for references from other entities.
+type dmDeleter struct {
+	dm       *gql.DeviceManagementClient
+	report   *OperationReport
+	entities map[string][]map[string]interface{}
+	deleted  map[dmEntityRef]bool
+}
+
+// Load all entities of a kind (cached for the life of the deleter).
+func (d *dmDeleter) load(ctx context.Context, kind *dmKind) ([]map[string]interface{}, error) {
+	if entities, ok := d.entities[kind.Name]; ok {
+		return entities, nil
+	}
+	list, err := listAllDmEntities(ctx, d.dm, kind, REFERENCE_PAGE_SIZE)
+	if err != nil {
+		return nil, err
+	}
+	entities := make([]map[string]interface{}, 0, len(list.Items))
+	for _, item := range list.Items {
+		fields, err := entityFields(item)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, fields)
+	}
+	d.entities[kind.Name] = entities
+	return entities, nil
+}
+
+// Find entities that reference the given entity and have not been deleted.
+func (d *dmDeleter) references(ctx context.Context, ref dmEntityRef) ([]dmEntityRef, error) {
+	found := make([]dmEntityRef, 0)
+	seen := make(map[dmEntityRef]bool)
+	for _, reference := range ref.kind.referencedBy() {
+		entities, err := d.load(ctx, reference.Kind)
+		if err != nil {
+			return nil, err
+		}
+		for _, entity := range entities {
+			if entityValue(entity, reference.Path) != ref.token {
+				continue
+			}
+			referrer := dmEntityRef{kind: reference.Kind, token: entityValue(entity, "token")}
+			if !seen[referrer] && !d.deleted[referrer] {
+				seen[referrer] = true
+				found = append(found, referrer)
+			}
+		}
+	}
+	return found, nil
+}
+
+// Describe a list of references for error messages.
+func describeReferences(refs []dmEntityRef) string {
+	parts := make([]string, 0)
+	for i, ref := range refs {
+		if i == MAX_LISTED_REFERENCES {
+			parts = append(parts, fmt.Sprintf("and %d more", len(refs)-i))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s '%s'", ref.kind.Title, ref.token))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Fail if any entity in a batch is referenced by entities outside of the batch. Referrers in
+// the batch are deleted first, so they do not prevent deleting the entities they reference.
+func (d *dmDeleter) checkUnreferenced(ctx context.Context, batch []dmEntityRef) error {
+	inBatch := make(map[dmEntityRef]bool)
+	for _, ref := range batch {
+		inBatch[ref] = true
+	}
+	for _, ref := range batch {
+		refs, err := d.references(ctx, ref)
+		if err != nil {
+			return err
+		}
+		outside := make([]dmEntityRef, 0, len(refs))
+		for _, referrer := range refs {
+			if !inBatch[referrer] {
+				outside = append(outside, referrer)
+			}
+		}
+		if len(outside) > 0 {
+			return fmt.Errorf("%s '%s' is referenced by %s (use --cascade to delete referencing entities)",
+				ref.kind.Title, ref.token, describeReferences(outside))
+		}
+	}
+	return nil
+}
+
+// Delete an entity, first deleting any entities that reference it.
+func (d *dmDeleter) delete(ctx context.Context, ref dmEntityRef) error {
+	if d.deleted[ref] {
+		return nil
+	}
+	refs, err := d.references(ctx, ref)
+	if err != nil {
+		return err
+	}
+	for _, referrer := range refs {
+		if err := d.delete(ctx, referrer); err != nil {
+			return err
+		}
+	}
+	deleted, err := ref.kind.delete(ctx, d.dm, ref.token)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%s '%s' was not deleted", ref.kind.Title, ref.token)
+	}
+	d.deleted[ref] = true
+	d.report.Step(ref.kind.Title, ref.token, "deleted")
+	fmt.Fprintf(output.Progress(), color.HiGreenString("Deleted %s '%s'.\n"), ref.kind.Title, ref.token)
+	return nil
+}
+
+// Create command that will delete entities of a device management kind
+func newDeleteDmKindCommand(kind *dmKind) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     fmt.Sprintf("%s token [token...]", kind.Singular),
+		Aliases: []string{kind.Name},
+		Short:   fmt.Sprintf("Delete %ss", kind.Title),
+		Long: fmt.Sprintf(`Deletes %ss by token. Entities referenced by relationships or groups are only
+deleted if --cascade is passed, in which case the referencing entities are deleted first.`, kind.Title),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cascade, _ := cmd.Flags().GetBool("cascade")
+			return deleteDmEntities(cmd, kind, args, cascade)
+		}}
+	addTargetFlags(cmd.Flags())
+	cmd.Flags().Bool("cascade", false, "also delete entities that reference the deleted entities")
+	return cmd
+}
+
+// Delete device management entities
+func deleteDmEntities(cmd *cobra.Command, kind *dmKind, tokens []string, cascade bool) error {
+	if len(tokens) < 1 {
+		return fmt.Errorf("no token passed for %s to delete", kind.Title)
+	}
+	dm, err := gql.NewDeviceManagementGraphQLClient(cmd)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	_, err = kind.getEntities(ctx, &dm, tokens)
+	if err != nil {
+		return err
+	}
+
+	deleter := &dmDeleter{
+		dm:       &dm,
+		report:   NewOperationReport("delete"),
+		entities: make(map[string][]map[string]interface{}),
+		deleted:  make(map[dmEntityRef]bool),
+	}
+
+	batch := make([]dmEntityRef, 0, len(tokens))
+	for _, token := range tokens {
+		batch = append(batch, dmEntityRef{kind: kind, token: token})
+	}
+
+	// Check all entities before deleting anything.
+	if !cascade {
+		if err := deleter.checkUnreferenced(ctx, batch); err != nil {
+			return err
+		}
+	}
+	for _, ref := range batch {
+		if err := deleter.delete(ctx, ref); err != nil {
+			return err
+		}
+	}
+	return deleter.report.Complete()
+}
+
+func init() {
+	for _, kind := range dmKinds {
+		deleteCmd.AddCommand(newDeleteDmKindCommand(kind))
+	}
+}
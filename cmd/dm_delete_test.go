@@ -0,0 +1,68 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// Deleter with the entities of every kind already loaded.
+func testDeleter(loaded map[string][]map[string]interface{}) *dmDeleter {
+	entities := make(map[string][]map[string]interface{})
+	for _, kind := range dmKinds {
+		entities[kind.Name] = loaded[kind.Name]
+	}
+	return &dmDeleter{
+		report:   NewOperationReport("delete"),
+		entities: entities,
+		deleted:  make(map[dmEntityRef]bool),
+	}
+}
+
+func TestCheckUnreferenced(t *testing.T) {
+	devices, err := findDmKind("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	relationships, err := findDmKind("device-relationships")
+	if err != nil {
+		t.Fatal(err)
+	}
+	truck := dmEntityRef{kind: devices, token: "truck-1"}
+	attached := dmEntityRef{kind: relationships, token: "attached-1"}
+	loaded := map[string][]map[string]interface{}{
+		"devices": {{"token": "truck-1"}, {"token": "trailer-1"}},
+		"device-relationships": {{"token": "attached-1", "source": map[string]interface{}{"token": "truck-1"},
+			"targetDevice": map[string]interface{}{"token": "trailer-1"}}},
+	}
+
+	tests := []struct {
+		name    string
+		batch   []dmEntityRef
+		wantErr string
+	}{
+		{name: "unreferenced", batch: []dmEntityRef{{kind: devices, token: "other-1"}}},
+		{name: "referenced", batch: []dmEntityRef{truck}, wantErr: "device relationship 'attached-1'"},
+		{name: "referrer in batch", batch: []dmEntityRef{truck, attached}},
+		{name: "referrer first in batch", batch: []dmEntityRef{attached, truck}},
+		{name: "referenced by other", batch: []dmEntityRef{truck, {kind: devices, token: "trailer-1"}},
+			wantErr: "device relationship 'attached-1'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testDeleter(loaded).checkUnreferenced(context.Background(), tt.batch)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,768 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	dmgql "github.com/devicechain-io/dc-device-management/gqlclient"
+	dmmodel "github.com/devicechain-io/dc-device-management/model"
+	gql "github.com/devicechain-io/dcctl/graphql"
+)
+
+// Columns displayed for every device management entity.
+var dmCommonColumns = []entityColumn{
+	{Header: "TOKEN", Path: "token"},
+	{Header: "NAME", Path: "name"},
+	{Header: "DESCRIPTION", Path: "description"},
+}
+
+// Columns displayed for every device management entity in wide output.
+var dmWideColumns = []entityColumn{
+	{Header: "ID", Path: "id", Wide: true},
+	{Header: "CREATED", Path: "createdAt", Wide: true},
+}
+
+// Role of a kind within its family of device management entities.
+type dmRole int
+
+const (
+	dmRoleType dmRole = iota
+	dmRoleEntity
+	dmRoleRelationshipType
+	dmRoleRelationship
+	dmRoleGroup
+	dmRoleGroupRelationshipType
+	dmRoleGroupRelationship
+)
+
+// Device management entity kind exposed by generic commands. Base is the entity
+// family (e.g. device) in the form used for JSON field names.
+type dmKind struct {
+	Name       string
+	Singular   string
+	Title      string
+	Base       string
+	Role       dmRole
+	Columns    []entityColumn
+	getByToken func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error)
+	list       func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error)
+	newRequest func() interface{}
+	create     func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error)
+}
+
+// All device management entity kinds.
+var dmKinds = []*dmKind{
+	{
+		Name:     "device-types",
+		Singular: "device-type",
+		Title:    "device type",
+		Base:     "device",
+		Role:     dmRoleType,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetDeviceTypesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListDeviceTypes(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.DeviceTypeCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.DeviceTypeCreateRequest)
+			return dm.AssureDeviceType(ctx, req.Token, req.Name, req.Description, req.ImageUrl, req.Icon,
+				req.BackgroundColor, req.ForegroundColor, req.BorderColor, req.Metadata)
+		},
+	},
+	{
+		Name:     "devices",
+		Singular: "device",
+		Title:    "device",
+		Base:     "device",
+		Role:     dmRoleEntity,
+		Columns:  []entityColumn{{Header: "TYPE", Path: "deviceType.token"}},
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetDevicesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListDevices(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.DeviceCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.DeviceCreateRequest)
+			return dm.AssureDevice(ctx, req.Token, req.DeviceTypeToken, req.Name, req.Description, req.Metadata)
+		},
+	},
+	{
+		Name:     "device-relationship-types",
+		Singular: "device-relationship-type",
+		Title:    "device relationship type",
+		Base:     "device",
+		Role:     dmRoleRelationshipType,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetDeviceRelationshipTypesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListDeviceRelationshipTypes(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.DeviceRelationshipTypeCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.DeviceRelationshipTypeCreateRequest)
+			return dm.AssureDeviceRelationshipType(ctx, req.Token, req.Name, req.Description, req.Metadata, req.Tracked)
+		},
+	},
+	{
+		Name:     "device-relationships",
+		Singular: "device-relationship",
+		Title:    "device relationship",
+		Base:     "device",
+		Role:     dmRoleRelationship,
+		Columns:  []entityColumn{{Header: "SOURCE", Path: "source.token"}, {Header: "TYPE", Path: "relationshipType.token"}},
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetDeviceRelationshipsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListDeviceRelationships(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.DeviceRelationshipCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.DeviceRelationshipCreateRequest)
+			return dm.AssureDeviceRelationship(ctx, req.Token, req.SourceDevice, req.Targets, req.RelationshipType, req.Metadata)
+		},
+	},
+	{
+		Name:     "device-groups",
+		Singular: "device-group",
+		Title:    "device group",
+		Base:     "device",
+		Role:     dmRoleGroup,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetDeviceGroupsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListDeviceGroups(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.DeviceGroupCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.DeviceGroupCreateRequest)
+			return dm.AssureDeviceGroup(ctx, req.Token, req.Name, req.Description, req.ImageUrl, req.Icon,
+				req.BackgroundColor, req.ForegroundColor, req.BorderColor, req.Metadata)
+		},
+	},
+	{
+		Name:     "device-group-relationship-types",
+		Singular: "device-group-relationship-type",
+		Title:    "device group relationship type",
+		Base:     "device",
+		Role:     dmRoleGroupRelationshipType,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetDeviceGroupRelationshipTypesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListDeviceGroupRelationshipTypes(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.DeviceGroupRelationshipTypeCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.DeviceGroupRelationshipTypeCreateRequest)
+			return dm.AssureDeviceGroupRelationshipType(ctx, req.Token, req.Name, req.Description, req.Metadata)
+		},
+	},
+	{
+		Name:     "device-group-relationships",
+		Singular: "device-group-relationship",
+		Title:    "device group relationship",
+		Base:     "device",
+		Role:     dmRoleGroupRelationship,
+		Columns:  []entityColumn{{Header: "SOURCE", Path: "source.token"}, {Header: "TYPE", Path: "relationshipType.token"}},
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetDeviceGroupRelationshipsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListDeviceGroupRelationships(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.DeviceGroupRelationshipCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.DeviceGroupRelationshipCreateRequest)
+			return dm.AssureDeviceGroupRelationship(ctx, req.Token, req.SourceDeviceGroup, req.Targets, req.RelationshipType, req.Metadata)
+		},
+	},
+	{
+		Name:     "asset-types",
+		Singular: "asset-type",
+		Title:    "asset type",
+		Base:     "asset",
+		Role:     dmRoleType,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAssetTypesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAssetTypes(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AssetTypeCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AssetTypeCreateRequest)
+			return dm.AssureAssetType(ctx, req.Token, req.Name, req.Description, req.ImageUrl, req.Icon,
+				req.BackgroundColor, req.ForegroundColor, req.BorderColor, req.Metadata)
+		},
+	},
+	{
+		Name:     "assets",
+		Singular: "asset",
+		Title:    "asset",
+		Base:     "asset",
+		Role:     dmRoleEntity,
+		Columns:  []entityColumn{{Header: "TYPE", Path: "assetType.token"}},
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAssetsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAssets(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AssetCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AssetCreateRequest)
+			return dm.AssureAsset(ctx, req.Token, req.AssetTypeToken, req.Name, req.Description, req.Metadata)
+		},
+	},
+	{
+		Name:     "asset-relationship-types",
+		Singular: "asset-relationship-type",
+		Title:    "asset relationship type",
+		Base:     "asset",
+		Role:     dmRoleRelationshipType,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAssetRelationshipTypesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAssetRelationshipTypes(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AssetRelationshipTypeCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AssetRelationshipTypeCreateRequest)
+			return dm.AssureAssetRelationshipType(ctx, req.Token, req.Name, req.Description, req.Metadata)
+		},
+	},
+	{
+		Name:     "asset-relationships",
+		Singular: "asset-relationship",
+		Title:    "asset relationship",
+		Base:     "asset",
+		Role:     dmRoleRelationship,
+		Columns:  []entityColumn{{Header: "SOURCE", Path: "source.token"}, {Header: "TYPE", Path: "relationshipType.token"}},
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAssetRelationshipsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAssetRelationships(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AssetRelationshipCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AssetRelationshipCreateRequest)
+			return dm.AssureAssetRelationship(ctx, req.Token, req.SourceAsset, req.Targets, req.RelationshipType, req.Metadata)
+		},
+	},
+	{
+		Name:     "asset-groups",
+		Singular: "asset-group",
+		Title:    "asset group",
+		Base:     "asset",
+		Role:     dmRoleGroup,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAssetGroupsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAssetGroups(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AssetGroupCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AssetGroupCreateRequest)
+			return dm.AssureAssetGroup(ctx, req.Token, req.Name, req.Description, req.ImageUrl, req.Icon,
+				req.BackgroundColor, req.ForegroundColor, req.BorderColor, req.Metadata)
+		},
+	},
+	{
+		Name:     "asset-group-relationship-types",
+		Singular: "asset-group-relationship-type",
+		Title:    "asset group relationship type",
+		Base:     "asset",
+		Role:     dmRoleGroupRelationshipType,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAssetGroupRelationshipTypesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAssetGroupRelationshipTypes(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AssetGroupRelationshipTypeCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AssetGroupRelationshipTypeCreateRequest)
+			return dm.AssureAssetGroupRelationshipType(ctx, req.Token, req.Name, req.Description, req.Metadata)
+		},
+	},
+	{
+		Name:     "asset-group-relationships",
+		Singular: "asset-group-relationship",
+		Title:    "asset group relationship",
+		Base:     "asset",
+		Role:     dmRoleGroupRelationship,
+		Columns:  []entityColumn{{Header: "SOURCE", Path: "source.token"}, {Header: "TYPE", Path: "relationshipType.token"}},
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAssetGroupRelationshipsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAssetGroupRelationships(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AssetGroupRelationshipCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AssetGroupRelationshipCreateRequest)
+			return dm.AssureAssetGroupRelationship(ctx, req.Token, req.SourceAssetGroup, req.Targets, req.RelationshipType, req.Metadata)
+		},
+	},
+	{
+		Name:     "area-types",
+		Singular: "area-type",
+		Title:    "area type",
+		Base:     "area",
+		Role:     dmRoleType,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAreaTypesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAreaTypes(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AreaTypeCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AreaTypeCreateRequest)
+			return dm.AssureAreaType(ctx, req.Token, req.Name, req.Description, req.ImageUrl, req.Icon,
+				req.BackgroundColor, req.ForegroundColor, req.BorderColor, req.Metadata)
+		},
+	},
+	{
+		Name:     "areas",
+		Singular: "area",
+		Title:    "area",
+		Base:     "area",
+		Role:     dmRoleEntity,
+		Columns:  []entityColumn{{Header: "TYPE", Path: "areaType.token"}},
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAreasByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAreas(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AreaCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AreaCreateRequest)
+			return dm.AssureArea(ctx, req.Token, req.AreaTypeToken, req.Name, req.Description, req.Metadata)
+		},
+	},
+	{
+		Name:     "area-relationship-types",
+		Singular: "area-relationship-type",
+		Title:    "area relationship type",
+		Base:     "area",
+		Role:     dmRoleRelationshipType,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAreaRelationshipTypesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAreaRelationshipTypes(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AreaRelationshipTypeCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AreaRelationshipTypeCreateRequest)
+			return dm.AssureAreaRelationshipType(ctx, req.Token, req.Name, req.Description, req.Metadata)
+		},
+	},
+	{
+		Name:     "area-relationships",
+		Singular: "area-relationship",
+		Title:    "area relationship",
+		Base:     "area",
+		Role:     dmRoleRelationship,
+		Columns:  []entityColumn{{Header: "SOURCE", Path: "source.token"}, {Header: "TYPE", Path: "relationshipType.token"}},
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAreaRelationshipsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAreaRelationships(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AreaRelationshipCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AreaRelationshipCreateRequest)
+			return dm.AssureAreaRelationship(ctx, req.Token, req.SourceArea, req.Targets, req.RelationshipType, req.Metadata)
+		},
+	},
+	{
+		Name:     "area-groups",
+		Singular: "area-group",
+		Title:    "area group",
+		Base:     "area",
+		Role:     dmRoleGroup,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAreaGroupsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAreaGroups(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AreaGroupCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AreaGroupCreateRequest)
+			return dm.AssureAreaGroup(ctx, req.Token, req.Name, req.Description, req.ImageUrl, req.Icon,
+				req.BackgroundColor, req.ForegroundColor, req.BorderColor, req.Metadata)
+		},
+	},
+	{
+		Name:     "area-group-relationship-types",
+		Singular: "area-group-relationship-type",
+		Title:    "area group relationship type",
+		Base:     "area",
+		Role:     dmRoleGroupRelationshipType,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAreaGroupRelationshipTypesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAreaGroupRelationshipTypes(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AreaGroupRelationshipTypeCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AreaGroupRelationshipTypeCreateRequest)
+			return dm.AssureAreaGroupRelationshipType(ctx, req.Token, req.Name, req.Description, req.Metadata)
+		},
+	},
+	{
+		Name:     "area-group-relationships",
+		Singular: "area-group-relationship",
+		Title:    "area group relationship",
+		Base:     "area",
+		Role:     dmRoleGroupRelationship,
+		Columns:  []entityColumn{{Header: "SOURCE", Path: "source.token"}, {Header: "TYPE", Path: "relationshipType.token"}},
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetAreaGroupRelationshipsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListAreaGroupRelationships(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.AreaGroupRelationshipCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.AreaGroupRelationshipCreateRequest)
+			return dm.AssureAreaGroupRelationship(ctx, req.Token, req.SourceAreaGroup, req.Targets, req.RelationshipType, req.Metadata)
+		},
+	},
+	{
+		Name:     "customer-types",
+		Singular: "customer-type",
+		Title:    "customer type",
+		Base:     "customer",
+		Role:     dmRoleType,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetCustomerTypesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListCustomerTypes(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.CustomerTypeCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.CustomerTypeCreateRequest)
+			return dm.AssureCustomerType(ctx, req.Token, req.Name, req.Description, req.ImageUrl, req.Icon,
+				req.BackgroundColor, req.ForegroundColor, req.BorderColor, req.Metadata)
+		},
+	},
+	{
+		Name:     "customers",
+		Singular: "customer",
+		Title:    "customer",
+		Base:     "customer",
+		Role:     dmRoleEntity,
+		Columns:  []entityColumn{{Header: "TYPE", Path: "customerType.token"}},
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetCustomersByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListCustomers(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.CustomerCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.CustomerCreateRequest)
+			return dm.AssureCustomer(ctx, req.Token, req.CustomerTypeToken, req.Name, req.Description, req.Metadata)
+		},
+	},
+	{
+		Name:     "customer-relationship-types",
+		Singular: "customer-relationship-type",
+		Title:    "customer relationship type",
+		Base:     "customer",
+		Role:     dmRoleRelationshipType,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetCustomerRelationshipTypesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListCustomerRelationshipTypes(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.CustomerRelationshipTypeCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.CustomerRelationshipTypeCreateRequest)
+			return dm.AssureCustomerRelationshipType(ctx, req.Token, req.Name, req.Description, req.Metadata)
+		},
+	},
+	{
+		Name:     "customer-relationships",
+		Singular: "customer-relationship",
+		Title:    "customer relationship",
+		Base:     "customer",
+		Role:     dmRoleRelationship,
+		Columns:  []entityColumn{{Header: "SOURCE", Path: "source.token"}, {Header: "TYPE", Path: "relationshipType.token"}},
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetCustomerRelationshipsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListCustomerRelationships(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.CustomerRelationshipCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.CustomerRelationshipCreateRequest)
+			return dm.AssureCustomerRelationship(ctx, req.Token, req.SourceCustomer, req.Targets, req.RelationshipType, req.Metadata)
+		},
+	},
+	{
+		Name:     "customer-groups",
+		Singular: "customer-group",
+		Title:    "customer group",
+		Base:     "customer",
+		Role:     dmRoleGroup,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetCustomerGroupsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListCustomerGroups(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.CustomerGroupCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.CustomerGroupCreateRequest)
+			return dm.AssureCustomerGroup(ctx, req.Token, req.Name, req.Description, req.ImageUrl, req.Icon,
+				req.BackgroundColor, req.ForegroundColor, req.BorderColor, req.Metadata)
+		},
+	},
+	{
+		Name:     "customer-group-relationship-types",
+		Singular: "customer-group-relationship-type",
+		Title:    "customer group relationship type",
+		Base:     "customer",
+		Role:     dmRoleGroupRelationshipType,
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetCustomerGroupRelationshipTypesByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListCustomerGroupRelationshipTypes(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.CustomerGroupRelationshipTypeCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.CustomerGroupRelationshipTypeCreateRequest)
+			return dm.AssureCustomerGroupRelationshipType(ctx, req.Token, req.Name, req.Description, req.Metadata)
+		},
+	},
+	{
+		Name:     "customer-group-relationships",
+		Singular: "customer-group-relationship",
+		Title:    "customer group relationship",
+		Base:     "customer",
+		Role:     dmRoleGroupRelationship,
+		Columns:  []entityColumn{{Header: "SOURCE", Path: "source.token"}, {Header: "TYPE", Path: "relationshipType.token"}},
+		getByToken: func(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) (interface{}, error) {
+			return dm.GetCustomerGroupRelationshipsByToken(ctx, tokens)
+		},
+		list: func(ctx context.Context, dm *gql.DeviceManagementClient, page int, size int) (interface{}, *dmgql.DefaultPagination, error) {
+			return dm.ListCustomerGroupRelationships(ctx, page, size)
+		},
+		newRequest: func() interface{} { return &dmmodel.CustomerGroupRelationshipCreateRequest{} },
+		create: func(ctx context.Context, dm *gql.DeviceManagementClient, request interface{}) (interface{}, bool, error) {
+			req := request.(*dmmodel.CustomerGroupRelationshipCreateRequest)
+			return dm.AssureCustomerGroupRelationship(ctx, req.Token, req.SourceCustomerGroup, req.Targets, req.RelationshipType, req.Metadata)
+		},
+	},
+}
+
+// Find a device management kind by plural or singular name.
+func findDmKind(name string) (*dmKind, error) {
+	for _, kind := range dmKinds {
+		if kind.Name == name || kind.Singular == name {
+			return kind, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown device management kind '%s'", name)
+}
+
+// Get all columns displayed for the kind.
+func (k *dmKind) allColumns() []entityColumn {
+	columns := make([]entityColumn, 0)
+	columns = append(columns, dmCommonColumns...)
+	columns = append(columns, k.Columns...)
+	return append(columns, dmWideColumns...)
+}
+
+// Get entities of the kind by token in the order requested. Fails if any token is not found.
+func (k *dmKind) getEntities(ctx context.Context, dm *gql.DeviceManagementClient, tokens []string) ([]interface{}, error) {
+	result, err := k.getByToken(ctx, dm, tokens)
+	if err != nil {
+		return nil, err
+	}
+	matches := reflect.ValueOf(result)
+	entities := make([]interface{}, 0, len(tokens))
+	for _, token := range tokens {
+		var match reflect.Value
+		if matches.IsValid() && !matches.IsNil() {
+			match = matches.MapIndex(reflect.ValueOf(token))
+		}
+		if !match.IsValid() || match.IsNil() {
+			return nil, fmt.Errorf("%s '%s' not found", k.Title, token)
+		}
+		entities = append(entities, match.Interface())
+	}
+	return entities, nil
+}
+
+// List a page of entities of the kind.
+func (k *dmKind) listEntities(ctx context.Context, dm *gql.DeviceManagementClient, page int,
+	size int) ([]interface{}, *dmgql.DefaultPagination, error) {
+	result, pagination, err := k.list(ctx, dm, page, size)
+	if err != nil {
+		return nil, nil, err
+	}
+	entities := make([]interface{}, 0)
+	values := reflect.ValueOf(result)
+	if values.IsValid() {
+		for i := 0; i < values.Len(); i++ {
+			entities = append(entities, values.Index(i).Interface())
+		}
+	}
+	return entities, pagination, nil
+}
+
+// Get the GraphQL type of entities of the kind (e.g. DeviceGroupRelationship).
+func (k *dmKind) graphqlType() string {
+	parts := strings.Split(k.Singular, "-")
+	for i := range parts {
+		parts[i] = upperFirst(parts[i])
+	}
+	return strings.Join(parts, "")
+}
+
+// Update an entity of the kind and return the entity as stored after the update.
+func (k *dmKind) update(ctx context.Context, dm *gql.DeviceManagementClient, token string, request interface{}) (interface{}, error) {
+	err := dm.UpdateEntity(ctx, k.graphqlType(), token, request)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := k.getEntities(ctx, dm, []string{token})
+	if err != nil {
+		return nil, err
+	}
+	return updated[0], nil
+}
+
+// Delete an entity of the kind.
+func (k *dmKind) delete(ctx context.Context, dm *gql.DeviceManagementClient, token string) (bool, error) {
+	return dm.DeleteEntity(ctx, k.graphqlType(), token)
+}
+
+// Get value at a dotted path in the JSON representation of an entity.
+func entityValue(entity map[string]interface{}, path string) string {
+	var current interface{} = entity
+	for _, part := range strings.Split(path, ".") {
+		fields, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = fields[part]
+	}
+	switch value := current.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case map[string]interface{}, []interface{}:
+		b, _ := json.Marshal(value)
+		return string(b)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// Convert an entity into a generic map based on its JSON representation.
+func entityFields(entity interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	err = json.Unmarshal(b, &fields)
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// Find the kind with the given base and role.
+func findDmKindByRole(base string, role dmRole) *dmKind {
+	for _, kind := range dmKinds {
+		if kind.Base == base && kind.Role == role {
+			return kind
+		}
+	}
+	return nil
+}
+
+// Convert first letter to uppercase.
+func upperFirst(val string) string {
+	if val == "" {
+		return val
+	}
+	return strings.ToUpper(val[:1]) + val[1:]
+}
+
+// Convert first letter to lowercase.
+func lowerFirst(val string) string {
+	if val == "" {
+		return val
+	}
+	return strings.ToLower(val[:1]) + val[1:]
+}
+
+// Reference to entities of a kind from entities of another kind. Path is a dotted
+// path to the referenced token in the referencing entity.
+type dmReference struct {
+	Kind *dmKind
+	Path string
+}
+
+// Get references that may point at entities of this kind.
+func (k *dmKind) referencedBy() []dmReference {
+	refs := make([]dmReference, 0)
+	switch k.Role {
+	case dmRoleType:
+		refs = append(refs, dmReference{Kind: findDmKindByRole(k.Base, dmRoleEntity), Path: k.Base + "Type.token"})
+	case dmRoleEntity, dmRoleGroup:
+		source := findDmKindByRole(k.Base, dmRoleRelationship)
+		target := "target" + upperFirst(k.Base)
+		if k.Role == dmRoleGroup {
+			source = findDmKindByRole(k.Base, dmRoleGroupRelationship)
+			target += "Group"
+		}
+		refs = append(refs, dmReference{Kind: source, Path: "source.token"})
+		for _, other := range dmKinds {
+			if other.Role == dmRoleRelationship || other.Role == dmRoleGroupRelationship {
+				refs = append(refs, dmReference{Kind: other, Path: target + ".token"})
+			}
+		}
+	case dmRoleRelationshipType:
+		refs = append(refs, dmReference{Kind: findDmKindByRole(k.Base, dmRoleRelationship), Path: "relationshipType.token"})
+	case dmRoleGroupRelationshipType:
+		refs = append(refs, dmReference{Kind: findDmKindByRole(k.Base, dmRoleGroupRelationship), Path: "relationshipType.token"})
+	}
+	return refs
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Khan/genqlient/graphql"
+	gql "github.com/devicechain-io/dcctl/graphql"
+)
+
+// Client that records requests and answers with canned response data.
+type recordingClient struct {
+	requests []*graphql.Request
+	data     string
+}
+
+func (c *recordingClient) MakeRequest(ctx context.Context, req *graphql.Request, resp *graphql.Response) error {
+	c.requests = append(c.requests, req)
+	return json.Unmarshal([]byte(c.data), resp.Data)
+}
+
+// GraphQL types of every device management kind as named in the device management schema.
+var dmKindGraphqlTypes = map[string]string{
+	"device-types":                      "DeviceType",
+	"devices":                           "Device",
+	"device-relationship-types":         "DeviceRelationshipType",
+	"device-relationships":              "DeviceRelationship",
+	"device-groups":                     "DeviceGroup",
+	"device-group-relationship-types":   "DeviceGroupRelationshipType",
+	"device-group-relationships":        "DeviceGroupRelationship",
+	"asset-types":                       "AssetType",
+	"assets":                            "Asset",
+	"asset-relationship-types":          "AssetRelationshipType",
+	"asset-relationships":               "AssetRelationship",
+	"asset-groups":                      "AssetGroup",
+	"asset-group-relationship-types":    "AssetGroupRelationshipType",
+	"asset-group-relationships":         "AssetGroupRelationship",
+	"area-types":                        "AreaType",
+	"areas":                             "Area",
+	"area-relationship-types":           "AreaRelationshipType",
+	"area-relationships":                "AreaRelationship",
+	"area-groups":                       "AreaGroup",
+	"area-group-relationship-types":     "AreaGroupRelationshipType",
+	"area-group-relationships":          "AreaGroupRelationship",
+	"customer-types":                    "CustomerType",
+	"customers":                         "Customer",
+	"customer-relationship-types":       "CustomerRelationshipType",
+	"customer-relationships":            "CustomerRelationship",
+	"customer-groups":                   "CustomerGroup",
+	"customer-group-relationship-types": "CustomerGroupRelationshipType",
+	"customer-group-relationships":      "CustomerGroupRelationship",
+}
+
+func TestDmKindOperations(t *testing.T) {
+	if len(dmKinds) != len(dmKindGraphqlTypes) {
+		t.Errorf("%d kinds, %d expected GraphQL types", len(dmKinds), len(dmKindGraphqlTypes))
+	}
+	for _, kind := range dmKinds {
+		t.Run(kind.Name, func(t *testing.T) {
+			entity, ok := dmKindGraphqlTypes[kind.Name]
+			if !ok {
+				t.Fatalf("no expected GraphQL type for %s", kind.Name)
+			}
+			if got := kind.graphqlType(); got != entity {
+				t.Fatalf("GraphQL type = %s, want %s", got, entity)
+			}
+
+			// Updates send the create request of the kind, which must match the request type in the schema.
+			request := kind.newRequest()
+			if name := reflect.TypeOf(request).Elem().Name(); name != entity+"CreateRequest" {
+				t.Errorf("request type = %s, want %sCreateRequest", name, entity)
+			}
+			cli := &recordingClient{data: fmt.Sprintf(`{"update%s": {"id": "1"}}`, entity)}
+			dm := gql.DeviceManagementClient{Client: cli}
+			if err := dm.UpdateEntity(context.Background(), kind.graphqlType(), "t1", request); err != nil {
+				t.Fatal(err)
+			}
+			checkOperation(t, cli.requests[0], "Update"+entity, "$request: "+entity+"CreateRequest!",
+				"update"+entity+"(token: $token, request: $request)")
+
+			cli = &recordingClient{data: fmt.Sprintf(`{"delete%s": true}`, entity)}
+			dm = gql.DeviceManagementClient{Client: cli}
+			deleted, err := kind.delete(context.Background(), &dm, "t1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !deleted {
+				t.Error("entity not deleted")
+			}
+			checkOperation(t, cli.requests[0], "Delete"+entity, "delete"+entity+"(token: $token)")
+		})
+	}
+}
+
+// Check the operation name of a request and that its query contains the given parts.
+func checkOperation(t *testing.T, req *graphql.Request, opName string, parts ...string) {
+	t.Helper()
+	if req.OpName != opName {
+		t.Errorf("operation = %s, want %s", req.OpName, opName)
+	}
+	for _, part := range parts {
+		if !strings.Contains(req.Query, part) {
+			t.Errorf("query does not contain %q:\n%s", part, req.Query)
+		}
+	}
+}
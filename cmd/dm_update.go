@@ -0,0 +1,175 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// Get the value of a request field from the JSON representation of an existing entity.
+// References to other entities are returned as the referenced token.
+func requestValueFromEntity(entity map[string]interface{}, field string) interface{} {
+	key := lowerFirst(field)
+	if value, ok := entity[key]; ok {
+		if _, isObject := value.(map[string]interface{}); !isObject {
+			return value
+		}
+		return entityValue(entity, key+".token")
+	}
+	if strings.HasPrefix(field, "Source") {
+		return entityValue(entity, "source.token")
+	}
+	if strings.HasSuffix(field, "Token") {
+		return entityValue(entity, lowerFirst(strings.TrimSuffix(field, "Token"))+".token")
+	}
+	return nil
+}
+
+// Build a request document (as used for merge patches) from an existing entity.
+func requestDocFromEntity(t reflect.Type, entity map[string]interface{}) map[string]interface{} {
+	doc := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		key := lowerFirst(field.Name)
+		if field.Type.Kind() == reflect.Struct {
+			doc[key] = requestDocFromEntity(field.Type, entity)
+			continue
+		}
+		value := requestValueFromEntity(entity, field.Name)
+		if value == nil || value == "" {
+			continue
+		}
+		// Decode metadata so patches may merge individual values.
+		if metadata, ok := value.(string); ok && field.Name == "Metadata" {
+			var decoded interface{}
+			if json.Unmarshal([]byte(metadata), &decoded) == nil {
+				value = decoded
+			}
+		}
+		doc[key] = value
+	}
+	return doc
+}
+
+// Get merge patch passed inline or in a file.
+func getMergePatchFromFlags(cmd *cobra.Command) ([]byte, error) {
+	filename, _ := cmd.Flags().GetString("filename")
+	patch, _ := cmd.Flags().GetString("patch")
+	if filename != "" && patch != "" {
+		return nil, errors.New("filename and patch may not be used together")
+	}
+	if filename != "" {
+		return readJsonOrYamlFile(filename)
+	}
+	if patch != "" {
+		b, err := yaml.YAMLToJSON([]byte(patch))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse patch: %v", err)
+		}
+		return b, nil
+	}
+	return nil, nil
+}
+
+// Create command that will update an entity of a device management kind
+func newUpdateDmKindCommand(kind *dmKind) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     fmt.Sprintf("%s token", kind.Singular),
+		Aliases: []string{kind.Name},
+		Short:   fmt.Sprintf("Update an existing %s", kind.Title),
+		Long: fmt.Sprintf(`Updates an existing %s. Changes are applied as a JSON merge patch passed in
+a file or inline, followed by any field flags.`, kind.Title),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateDmEntity(cmd, kind, args)
+		}}
+	addTargetFlags(cmd.Flags())
+	cmd.Flags().StringP("filename", "f", "", "JSON or YAML merge patch file ('-' for stdin)")
+	cmd.Flags().StringP("patch", "p", "", "JSON or YAML merge patch")
+	addRequestFlags(cmd.Flags(), kind)
+	return cmd
+}
+
+// Update a device management entity
+func updateDmEntity(cmd *cobra.Command, kind *dmKind, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("no token passed for %s to update", kind.Title)
+	}
+	token := args[0]
+	patch, err := getMergePatchFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	dm, err := gql.NewDeviceManagementGraphQLClient(cmd)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	existing, err := kind.getEntities(ctx, &dm, []string{token})
+	if err != nil {
+		return err
+	}
+	fields, err := entityFields(existing[0])
+	if err != nil {
+		return err
+	}
+
+	// Apply merge patch to request built from existing entity.
+	request := kind.newRequest()
+	doc, err := json.Marshal(requestDocFromEntity(reflect.TypeOf(request).Elem(), fields))
+	if err != nil {
+		return err
+	}
+	if patch != nil {
+		doc, err = jsonpatch.MergePatch(doc, patch)
+		if err != nil {
+			return fmt.Errorf("unable to apply patch: %v", err)
+		}
+	}
+	err = decodeRequest(doc, request)
+	if err != nil {
+		return err
+	}
+	err = applyRequestFlags(cmd.Flags(), request)
+	if err != nil {
+		return err
+	}
+	reflect.ValueOf(request).Elem().FieldByName("Token").SetString(token)
+	err = checkRequiredFields(request, kind)
+	if err != nil {
+		return err
+	}
+
+	updated, err := kind.update(ctx, &dm, token, request)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output.Progress(), color.HiGreenString("Updated %s '%s'.\n"), kind.Title, token)
+	return output.PrintResult(updated)
+}
+
+func init() {
+	for _, kind := range dmKinds {
+		updateCmd.AddCommand(newUpdateDmKindCommand(kind))
+	}
+}
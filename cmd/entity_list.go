@@ -0,0 +1,76 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"github.com/devicechain-io/dcctl/output"
+)
+
+// Column displayed for entities returned by remote calls. Path is a dotted path into
+// the JSON representation of the entity. Value (if set) derives the displayed value
+// from the entity fields instead.
+type entityColumn struct {
+	Header string
+	Path   string
+	Wide   bool
+	Value  func(fields map[string]interface{}) string
+}
+
+// Get the value displayed in the column for an entity.
+func (c entityColumn) value(fields map[string]interface{}) string {
+	if c.Value != nil {
+		return c.Value(fields)
+	}
+	return entityValue(fields, c.Path)
+}
+
+// Render entities as a table with the given columns. Entities are converted to
+// generic fields based on their JSON representation.
+func entityTable(columns []entityColumn, items []interface{}) *output.Table {
+	headers := make([]output.Column, 0, len(columns))
+	for _, column := range columns {
+		headers = append(headers, output.Column{Header: column.Header, Wide: column.Wide})
+	}
+	table := output.NewTable(headers...)
+	for _, item := range items {
+		fields, err := entityFields(item)
+		if err != nil {
+			continue
+		}
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			row = append(row, column.value(fields))
+		}
+		table.AddRow(row...)
+	}
+	return table
+}
+
+// Convert generic entities into list items.
+func entityItems(entities []map[string]interface{}) []interface{} {
+	items := make([]interface{}, 0, len(entities))
+	for _, entity := range entities {
+		items = append(items, entity)
+	}
+	return items
+}
+
+// Walk every page of a search. The fetch function returns the entities on a page and
+// the total number of matching records (negative if unknown).
+func listAllPages(fetch func(page int) ([]interface{}, int, error)) ([]interface{}, error) {
+	all := make([]interface{}, 0)
+	for page := 1; ; page++ {
+		items, total, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) == 0 || total < 0 || len(all) >= total {
+			return all, nil
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/devicechain-io/dcctl/output"
+)
+
+func TestEntityTable(t *testing.T) {
+	type device struct {
+		Token      string            `json:"token"`
+		DeviceType map[string]string `json:"deviceType"`
+	}
+	columns := []entityColumn{
+		{Header: "TOKEN", Path: "token"},
+		{Header: "TYPE", Path: "deviceType.token", Wide: true},
+		{Header: "UPPER", Value: func(fields map[string]interface{}) string { return entityValue(fields, "token") + "!" }},
+	}
+	items := []interface{}{
+		device{Token: "d1", DeviceType: map[string]string{"token": "sensor"}},
+		map[string]interface{}{"token": "d2"},
+	}
+	table := entityTable(columns, items)
+	wantColumns := []output.Column{{Header: "TOKEN"}, {Header: "TYPE", Wide: true}, {Header: "UPPER"}}
+	if !reflect.DeepEqual(table.Columns, wantColumns) {
+		t.Errorf("columns = %v", table.Columns)
+	}
+	wantRows := [][]string{{"d1", "sensor", "d1!"}, {"d2", "", "d2!"}}
+	if !reflect.DeepEqual(table.Rows, wantRows) {
+		t.Errorf("rows = %v", table.Rows)
+	}
+}
+
+func TestListAllPages(t *testing.T) {
+	tests := []struct {
+		name      string
+		pages     [][]interface{}
+		total     int
+		wantItems int
+		wantPages int
+	}{
+		{name: "all pages", pages: [][]interface{}{{1, 2}, {3, 4}, {5}}, total: 5, wantItems: 5, wantPages: 3},
+		{name: "stops at total", pages: [][]interface{}{{1, 2}, {3, 4}, {5}}, total: 4, wantItems: 4, wantPages: 2},
+		{name: "stops at empty page", pages: [][]interface{}{{1, 2}, {}}, total: 10, wantItems: 2, wantPages: 2},
+		{name: "unknown total", pages: [][]interface{}{{1, 2}, {3}}, total: -1, wantItems: 2, wantPages: 1},
+		{name: "no results", pages: [][]interface{}{{}}, total: 0, wantItems: 0, wantPages: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetched := 0
+			items, err := listAllPages(func(page int) ([]interface{}, int, error) {
+				fetched++
+				if page > len(tt.pages) {
+					t.Fatalf("requested page %d", page)
+				}
+				return tt.pages[page-1], tt.total, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != tt.wantItems || fetched != tt.wantPages {
+				t.Errorf("got %d items from %d pages, want %d items from %d pages", len(items), fetched, tt.wantItems, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestListAllPagesError(t *testing.T) {
+	failure := errors.New("unavailable")
+	_, err := listAllPages(func(page int) ([]interface{}, int, error) {
+		if page == 2 {
+			return nil, -1, failure
+		}
+		return []interface{}{page}, 10, nil
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("error = %v", err)
+	}
+}
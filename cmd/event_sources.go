@@ -0,0 +1,407 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Functional area of microservices that host event sources.
+	EVENT_SOURCES_FUNCTIONAL_AREA = "event-sources"
+
+	// Configuration key holding the event sources read by the microservice.
+	EVENT_SOURCES_KEY = "EventSources"
+
+	// Key used by dcctl to stash disabled event sources in the configuration. The microservice
+	// does not read this key, so disabled sources are simply absent from its source list.
+	DISABLED_EVENT_SOURCES_KEY = "DisabledEventSources"
+)
+
+// Create common command for managing event sources
+var eventSourcesCmd = &cobra.Command{
+	Use:     "event-sources",
+	Aliases: []string{"event-source", "es"},
+	Short:   "Manage event sources",
+	Long: `Inspects and manages event sources configured for the event-sources microservices of an
+instance. Sources are read from the MicroserviceConfiguration referenced by each microservice.
+Disabling a source removes it from the sources read by the microservice and stashes it under
+a separate key (maintained by dcctl) so it may be enabled again.`,
+}
+
+// Event source declared in a microservice configuration.
+type EventSource struct {
+	Id            string                 `json:"id"`
+	Type          string                 `json:"type"`
+	Enabled       bool                   `json:"enabled"`
+	Microservices []string               `json:"microservices"`
+	Configuration string                 `json:"configuration"`
+	Endpoint      string                 `json:"endpoint,omitempty"`
+	Topics        []string               `json:"topics,omitempty"`
+	Decoder       string                 `json:"decoder,omitempty"`
+	Debug         bool                   `json:"debug"`
+	Settings      map[string]interface{} `json:"settings,omitempty"`
+}
+
+// List of event sources.
+type EventSourceList struct {
+	Instance string         `json:"instance"`
+	Items    []*EventSource `json:"items"`
+}
+
+// Render event sources as a table.
+func (list *EventSourceList) Table() *output.Table {
+	table := output.NewTable(output.Column{Header: "ID"}, output.Column{Header: "TYPE"},
+		output.Column{Header: "STATUS"}, output.Column{Header: "ENDPOINT"}, output.Column{Header: "TOPICS"},
+		output.Column{Header: "DECODER", Wide: true}, output.Column{Header: "MICROSERVICES", Wide: true},
+		output.Column{Header: "CONFIGURATION", Wide: true})
+	for _, source := range list.Items {
+		status := "enabled"
+		if !source.Enabled {
+			status = "disabled"
+		}
+		table.AddRow(source.Id, source.Type, status, source.Endpoint, strings.Join(source.Topics, ","),
+			source.Decoder, strings.Join(source.Microservices, ","), source.Configuration)
+	}
+	return table
+}
+
+// Microservice configuration that declares event sources.
+type eventSourcesConfiguration struct {
+	object        *unstructured.Unstructured
+	microservices []string
+}
+
+// Get the name of the configuration resource.
+func (c *eventSourcesConfiguration) name() string {
+	return c.object.GetName()
+}
+
+// Get the entries declared under a configuration key.
+func (c *eventSourcesConfiguration) entries(key string) []interface{} {
+	entries, _, _ := unstructured.NestedSlice(c.object.Object, "spec", "configuration", key)
+	return entries
+}
+
+// Get all event sources declared in the configuration.
+func (c *eventSourcesConfiguration) sources() []*EventSource {
+	sources := make([]*EventSource, 0)
+	for _, key := range []string{EVENT_SOURCES_KEY, DISABLED_EVENT_SOURCES_KEY} {
+		for _, entry := range c.entries(key) {
+			fields, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			source := newEventSource(fields)
+			source.Enabled = key == EVENT_SOURCES_KEY
+			source.Configuration = c.name()
+			source.Microservices = c.microservices
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// Move an event source between the active and disabled lists. Returns false if the source
+// was not found or is already in the requested state.
+func (c *eventSourcesConfiguration) setEnabled(id string, enabled bool) (bool, error) {
+	from, to := EVENT_SOURCES_KEY, DISABLED_EVENT_SOURCES_KEY
+	if enabled {
+		from, to = to, from
+	}
+	remaining := make([]interface{}, 0)
+	moved := c.entries(to)
+	found := false
+	for _, entry := range c.entries(from) {
+		if fields, ok := entry.(map[string]interface{}); ok && entityValue(fields, "Id") == id {
+			moved = append(moved, entry)
+			found = true
+			continue
+		}
+		remaining = append(remaining, entry)
+	}
+	if !found {
+		return false, nil
+	}
+	if err := unstructured.SetNestedSlice(c.object.Object, remaining, "spec", "configuration", from); err != nil {
+		return false, err
+	}
+	if err := unstructured.SetNestedSlice(c.object.Object, moved, "spec", "configuration", to); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Build an event source from its configuration entry.
+func newEventSource(fields map[string]interface{}) *EventSource {
+	source := &EventSource{
+		Id:      entityValue(fields, "Id"),
+		Type:    entityValue(fields, "Type"),
+		Decoder: entityValue(fields, "Decoder.Type"),
+		Topics:  make([]string, 0),
+	}
+	source.Debug, _ = fields["Debug"].(bool)
+	settings, _ := fields["Configuration"].(map[string]interface{})
+	source.Settings = settings
+	source.Endpoint = eventSourceEndpoint(source.Type, settings)
+	if topic := entityValue(settings, "topic"); topic != "" {
+		source.Topics = append(source.Topics, topic)
+	}
+	if topics, ok := settings["topics"].([]interface{}); ok {
+		for _, topic := range topics {
+			source.Topics = append(source.Topics, fmt.Sprintf("%v", topic))
+		}
+	}
+	return source
+}
+
+// Derive the connection endpoint for an event source from its settings.
+func eventSourceEndpoint(sourceType string, settings map[string]interface{}) string {
+	if url := entityValue(settings, "url"); url != "" {
+		return url
+	}
+	host := entityValue(settings, "host")
+	if host == "" {
+		return ""
+	}
+	if port := entityValue(settings, "port"); port != "" {
+		host = fmt.Sprintf("%s:%s", host, port)
+	}
+	if sourceType == "" {
+		return host
+	}
+	return fmt.Sprintf("%s://%s", strings.ToLower(sourceType), host)
+}
+
+// Load configurations of event-sources microservices for an instance.
+func loadEventSourcesConfigurations(ctx context.Context, instance string) ([]*eventSourcesConfiguration, error) {
+	microservices := &v1beta1.MicroserviceList{}
+	err := v1beta1.V1Beta1Client.List(ctx, microservices, client.InNamespace(instance))
+	if err != nil {
+		return nil, err
+	}
+	configs := make([]*eventSourcesConfiguration, 0)
+	byName := make(map[string]*eventSourcesConfiguration)
+	for _, ms := range microservices.Items {
+		name := ms.Spec.ConfigurationId
+		if existing, ok := byName[name]; ok {
+			existing.microservices = append(existing.microservices, ms.Name)
+			continue
+		}
+		object := &unstructured.Unstructured{}
+		object.SetGroupVersionKind(v1beta1.GroupVersion.WithKind("MicroserviceConfiguration"))
+		err := v1beta1.V1Beta1Client.Get(ctx, types.NamespacedName{Name: name}, object)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get configuration '%s' for microservice '%s': %v", name, ms.Name, err)
+		}
+		area, _, _ := unstructured.NestedString(object.Object, "spec", "functionalArea")
+		if area != EVENT_SOURCES_FUNCTIONAL_AREA {
+			continue
+		}
+		cfg := &eventSourcesConfiguration{object: object, microservices: []string{ms.Name}}
+		byName[name] = cfg
+		configs = append(configs, cfg)
+	}
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("no event-sources microservices found for instance '%s'", instance)
+	}
+	return configs, nil
+}
+
+// Get all event sources for an instance sorted by id.
+func listEventSources(ctx context.Context, instance string) (*EventSourceList, error) {
+	configs, err := loadEventSourcesConfigurations(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	list := &EventSourceList{Instance: instance, Items: make([]*EventSource, 0)}
+	for _, cfg := range configs {
+		list.Items = append(list.Items, cfg.sources()...)
+	}
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		return list.Items[i].Id < list.Items[j].Id
+	})
+	return list, nil
+}
+
+// Create command that will list event sources
+func newEventSourcesListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List event sources",
+		Long:         `Lists event sources with their status, endpoints and topics`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list, err := listEventSources(context.Background(), config.Instance.Resolve(cmd.Flags()))
+			if err != nil {
+				return err
+			}
+			return output.Print(list)
+		}}
+}
+
+// Create command that will describe event sources
+func newEventSourcesDescribeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "describe id...",
+		Short:        "Show details of event sources",
+		Long:         `Shows connection endpoints, topics, decoder and settings of event sources`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list, err := listEventSources(context.Background(), config.Instance.Resolve(cmd.Flags()))
+			if err != nil {
+				return err
+			}
+			selected := &EventSourceList{Instance: list.Instance, Items: make([]*EventSource, 0)}
+			for _, id := range args {
+				found := false
+				for _, source := range list.Items {
+					if source.Id == id {
+						selected.Items = append(selected.Items, source)
+						found = true
+					}
+				}
+				if !found {
+					return fmt.Errorf("event source '%s' not found in instance '%s'", id, list.Instance)
+				}
+			}
+			return output.PrintDetails(selected, func(w io.Writer) error {
+				for _, source := range selected.Items {
+					describeEventSource(w, source)
+				}
+				return nil
+			})
+		}}
+}
+
+// Write details of an event source.
+func describeEventSource(w io.Writer, source *EventSource) {
+	status := color.HiGreenString("enabled")
+	if !source.Enabled {
+		status = color.HiRedString("disabled")
+	}
+	fmt.Fprintln(w, WhiteUnderline(fmt.Sprintf("Event Source %s", source.Id)))
+	fmt.Fprintf(w, "Type:           %s\n", source.Type)
+	fmt.Fprintf(w, "Status:         %s\n", status)
+	fmt.Fprintf(w, "Endpoint:       %s\n", source.Endpoint)
+	fmt.Fprintf(w, "Topics:         %s\n", strings.Join(source.Topics, ", "))
+	fmt.Fprintf(w, "Decoder:        %s\n", source.Decoder)
+	fmt.Fprintf(w, "Debug:          %t\n", source.Debug)
+	fmt.Fprintf(w, "Configuration:  %s\n", source.Configuration)
+	fmt.Fprintf(w, "Microservices:  %s\n", strings.Join(source.Microservices, ", "))
+	keys := make([]string, 0, len(source.Settings))
+	for key := range source.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		fmt.Fprintln(w, "Settings:")
+		for _, key := range keys {
+			fmt.Fprintf(w, "  %s: %s\n", key, entityValue(source.Settings, key))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// Enable or disable event sources by moving them between the active and disabled lists.
+func setEventSourcesEnabled(cmd *cobra.Command, ids []string, enabled bool) error {
+	ctx := context.Background()
+	instance := config.Instance.Resolve(cmd.Flags())
+	configs, err := loadEventSourcesConfigurations(ctx, instance)
+	if err != nil {
+		return err
+	}
+	action, state := "disable", "disabled"
+	if enabled {
+		action, state = "enable", "enabled"
+	}
+	report := NewOperationReport(fmt.Sprintf("%s event sources", action))
+	for _, id := range ids {
+		found := false
+		for _, cfg := range configs {
+			for _, source := range cfg.sources() {
+				if source.Id == id {
+					found = true
+				}
+			}
+			changed, err := cfg.setEnabled(id, enabled)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				continue
+			}
+			err = v1beta1.V1Beta1Client.Update(ctx, cfg.object)
+			if err != nil {
+				return fmt.Errorf("unable to %s event source '%s' in '%s': %v", action, id, cfg.name(), err)
+			}
+			report.Step("event source", id, state)
+			fmt.Fprintf(output.Progress(), color.HiGreenString("Event source '%s' %s in configuration '%s'.\n"), id, state, cfg.name())
+		}
+		if !found {
+			return fmt.Errorf("event source '%s' not found in instance '%s'", id, instance)
+		}
+	}
+	if len(report.Steps) == 0 {
+		fmt.Fprintf(output.Progress(), "Event sources already %s.\n", state)
+	}
+	return report.Complete()
+}
+
+// Create command that will enable event sources
+func newEventSourcesEnableCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "enable id...",
+		Short: "Enable event sources",
+		Long: `Enables event sources that were previously disabled with dcctl by moving them from the
+stashed list back into the sources read by the microservice.`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setEventSourcesEnabled(cmd, args, true)
+		}}
+}
+
+// Create command that will disable event sources
+func newEventSourcesDisableCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable id...",
+		Short: "Disable event sources",
+		Long: `Disables event sources so that the microservice no longer receives events from them.
+Event sources have no enabled flag, so a disabled source is removed from the 'EventSources'
+list of the microservice configuration. Its configuration is stashed under the
+'DisabledEventSources' key, which is only read by dcctl, so it may be enabled again with
+'dcctl event-sources enable'. Editing the configuration by other means may lose the stash.`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setEventSourcesEnabled(cmd, args, false)
+		}}
+}
+
+func init() {
+	eventSourcesCmd.PersistentFlags().StringP("instance", "i", "", "instance id whose event sources are managed")
+	eventSourcesCmd.AddCommand(newEventSourcesListCommand())
+	eventSourcesCmd.AddCommand(newEventSourcesDescribeCommand())
+	eventSourcesCmd.AddCommand(newEventSourcesEnableCommand())
+	eventSourcesCmd.AddCommand(newEventSourcesDisableCommand())
+	rootCmd.AddCommand(requireKubernetes(eventSourcesCmd))
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/spf13/cobra"
+)
+
+// Create common command for working with device events
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	Aliases: []string{"event"},
+	Short:   "Query device events",
+	Long:    `Queries events stored by event management for devices and assignments`,
+}
+
+// Event types that may be used as filters. Names map to GraphQL enum values (e.g. state-change to STATE_CHANGE).
+var eventTypes = []string{"location", "measurement", "alert", "command-invocation", "command-response", "state-change"}
+
+// Convert an event type name passed on the command line to its GraphQL enum value.
+func eventTypeEnum(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, eventType := range eventTypes {
+		if eventType == name {
+			return strings.ToUpper(strings.ReplaceAll(name, "-", "_")), nil
+		}
+	}
+	return "", fmt.Errorf("unknown event type '%s' (expected one of %s)", name, strings.Join(eventTypes, "|"))
+}
+
+// Columns displayed for events. Details are derived from the event type.
+var eventColumns = []entityColumn{
+	{Header: "ID", Path: "id", Wide: true},
+	{Header: "TYPE", Path: "eventType"},
+	{Header: "DEVICE", Path: "device.token"},
+	{Header: "ASSIGNMENT", Path: "deviceAssignment.token", Wide: true},
+	{Header: "OCCURRED", Path: "occurredTime"},
+	{Header: "PROCESSED", Path: "processedTime", Wide: true},
+	{Header: "DETAILS", Value: eventDetails},
+}
+
+// Page of device events.
+type EventList struct {
+	Items        []interface{} `json:"items"`
+	PageNumber   int           `json:"pageNumber,omitempty"`
+	PageSize     int           `json:"pageSize,omitempty"`
+	TotalRecords int           `json:"totalRecords"`
+}
+
+// Render events as a table.
+func (list *EventList) Table() *output.Table {
+	return entityTable(eventColumns, list.Items)
+}
+
+// Summarize the payload of an event based on its type.
+func eventDetails(fields map[string]interface{}) string {
+	value := func(path string) string { return entityValue(fields, path) }
+	switch strings.ToUpper(value("eventType")) {
+	case "LOCATION":
+		details := fmt.Sprintf("lat=%s lon=%s", value("latitude"), value("longitude"))
+		if elevation := value("elevation"); elevation != "" {
+			details += fmt.Sprintf(" elev=%s", elevation)
+		}
+		return details
+	case "MEASUREMENT":
+		return fmt.Sprintf("%s=%s", value("name"), value("value"))
+	case "ALERT":
+		return strings.TrimSpace(fmt.Sprintf("%s %s %s", value("level"), value("alertType"), value("message")))
+	case "COMMAND_INVOCATION":
+		return strings.TrimSpace(fmt.Sprintf("%s %s", value("command.token"), value("parameters")))
+	case "COMMAND_RESPONSE":
+		return strings.TrimSpace(fmt.Sprintf("%s %s", value("originatingEventId"), value("response")))
+	case "STATE_CHANGE":
+		return fmt.Sprintf("%s: %s -> %s", value("attribute"), value("previousState"), value("newState"))
+	}
+	return ""
+}
+
+func init() {
+	addTargetFlags(eventsCmd.PersistentFlags())
+	rootCmd.AddCommand(eventsCmd)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/spf13/cobra"
+)
+
+// Intialize command for listing events
+var eventsListCmd = NewEventsListCommand()
+
+// Create command that will list events
+func NewEventsListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List device events",
+		Long: `Lists events for a device or assignment a page at a time. Events may be filtered
+by type and by the time range in which they occurred.`,
+		Example: `  dcctl events list --device SDK7GV3WXZ3FBXZ --type measurement --since 1h
+  dcctl events list --assignment asn1 --start 2022-06-01T00:00:00Z --end 2022-06-02T00:00:00Z -o csv`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listEvents(cmd)
+		}}
+	addEventFilterFlags(cmd)
+	cmd.Flags().Duration("since", 0, "only include events that occurred within the given duration (e.g. 15m)")
+	cmd.Flags().String("start", "", "only include events that occurred at or after the given time (RFC3339)")
+	cmd.Flags().String("end", "", "only include events that occurred before the given time (RFC3339)")
+	cmd.Flags().Int("page", 1, "page number to display")
+	cmd.Flags().Int("page-size", 100, "number of events per page")
+	cmd.Flags().Bool("all", false, "list every page of events")
+	return cmd
+}
+
+// Add flags used to select events by device, assignment and type.
+func addEventFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("device", "", "token of device that reported events")
+	cmd.Flags().String("assignment", "", "token of device assignment that events were recorded for")
+	cmd.Flags().String("type", "", fmt.Sprintf("type of event (%s)", strings.Join(eventTypes, "|")))
+}
+
+// Build event search criteria from the device, assignment and type flags.
+func eventCriteriaFromFlags(cmd *cobra.Command) (gql.EventSearchCriteria, error) {
+	criteria := gql.EventSearchCriteria{}
+	if device, _ := cmd.Flags().GetString("device"); device != "" {
+		criteria.DeviceToken = &device
+	}
+	if assignment, _ := cmd.Flags().GetString("assignment"); assignment != "" {
+		criteria.AssignmentToken = &assignment
+	}
+	if name, _ := cmd.Flags().GetString("type"); name != "" {
+		eventType, err := eventTypeEnum(name)
+		if err != nil {
+			return criteria, err
+		}
+		criteria.EventType = &eventType
+	}
+	return criteria, nil
+}
+
+// Parse an optional RFC3339 time flag.
+func timeFlag(cmd *cobra.Command, name string) (*time.Time, error) {
+	value, _ := cmd.Flags().GetString(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s time '%s' (expected RFC3339 such as 2022-06-01T00:00:00Z)", name, value)
+	}
+	return &parsed, nil
+}
+
+// Apply time range flags to event search criteria.
+func applyTimeRangeFlags(cmd *cobra.Command, criteria *gql.EventSearchCriteria) error {
+	since, _ := cmd.Flags().GetDuration("since")
+	start, err := timeFlag(cmd, "start")
+	if err != nil {
+		return err
+	}
+	end, err := timeFlag(cmd, "end")
+	if err != nil {
+		return err
+	}
+	if since != 0 && start != nil {
+		return errors.New("--since and --start may not be used together")
+	}
+	if since < 0 {
+		return errors.New("--since must be a positive duration")
+	}
+	if since > 0 {
+		from := time.Now().Add(-since)
+		start = &from
+	}
+	if start != nil && end != nil && !start.Before(*end) {
+		return errors.New("--start must be before --end")
+	}
+	criteria.StartTime = start
+	criteria.EndTime = end
+	return nil
+}
+
+// List all pages of events that meet criteria.
+func listAllEvents(ctx context.Context, em *gql.EventManagementClient, criteria gql.EventSearchCriteria) (*EventList, error) {
+	items, err := listAllPages(func(page int) ([]interface{}, int, error) {
+		criteria.PageNumber = page
+		events, pagination, err := em.ListEvents(ctx, criteria)
+		if err != nil || pagination == nil {
+			return entityItems(events), -1, err
+		}
+		return entityItems(events), pagination.GetTotalRecords(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &EventList{Items: items, TotalRecords: len(items)}, nil
+}
+
+// List events that meet the criteria passed as flags
+func listEvents(cmd *cobra.Command) error {
+	page, _ := cmd.Flags().GetInt("page")
+	size, _ := cmd.Flags().GetInt("page-size")
+	all, _ := cmd.Flags().GetBool("all")
+	if page < 1 || size < 1 {
+		return errors.New("page and page-size must be greater than zero")
+	}
+	criteria, err := eventCriteriaFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if err := applyTimeRangeFlags(cmd, &criteria); err != nil {
+		return err
+	}
+	criteria.PageNumber = page
+	criteria.PageSize = size
+
+	em, err := gql.NewEventManagementGraphQLClient(cmd)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	// Walk every page if requested.
+	if all {
+		list, err := listAllEvents(ctx, &em, criteria)
+		if err != nil {
+			return err
+		}
+		return output.Print(list)
+	}
+
+	events, pagination, err := em.ListEvents(ctx, criteria)
+	if err != nil {
+		return err
+	}
+	list := &EventList{Items: entityItems(events), PageNumber: page, PageSize: size}
+	if pagination != nil {
+		list.TotalRecords = pagination.GetTotalRecords()
+	}
+	err = output.Print(list)
+	if err != nil {
+		return err
+	}
+	if !output.Structured() && list.TotalRecords > page*size {
+		pages := (list.TotalRecords + size - 1) / size
+		fmt.Fprintf(output.Progress(), "\nShowing page %d of %d (%d events). Use --page or --all to see more.\n",
+			page, pages, list.TotalRecords)
+	}
+	return nil
+}
+
+func init() {
+	eventsCmd.AddCommand(eventsListCmd)
+}
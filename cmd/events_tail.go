@@ -0,0 +1,274 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Maximum delay between attempts to reconnect to event management.
+	TAIL_MAX_BACKOFF = 30 * time.Second
+)
+
+// Intialize command for tailing events
+var eventsTailCmd = NewEventsTailCommand()
+
+// Create command that will stream events as they are stored
+func NewEventsTailCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tail",
+		Short: "Stream device events as they are stored",
+		Long: `Streams events as they are stored by event management. Event management is polled
+with a cursor based on the time of the last event seen. Events that are stored late
+are picked up as long as they occurred within the lookback window. Connection errors
+are retried with backoff until the command is interrupted.`,
+		Example: `  dcctl events tail --device SDK7GV3WXZ3FBXZ --type measurement
+  dcctl events tail --device SDK7GV3WXZ3FBXZ --since 10m -o json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return tailEvents(cmd)
+		}}
+	addEventFilterFlags(cmd)
+	cmd.Flags().Duration("since", 0, "also show events that occurred within the given duration before starting")
+	cmd.Flags().Duration("interval", 2*time.Second, "interval between polls for new events")
+	cmd.Flags().Duration("lookback", 30*time.Second, "window before the cursor that is checked for events stored late")
+	cmd.Flags().Int("page-size", 100, "number of events requested per page")
+	return cmd
+}
+
+// Cursor used to find events that have not been seen yet.
+type eventCursor struct {
+	from     time.Time
+	position time.Time
+	lookback time.Duration
+	seen     map[string]time.Time
+}
+
+// Start time for the next poll.
+func (c *eventCursor) start() time.Time {
+	return c.position.Add(-c.lookback)
+}
+
+// Filter events that have already been seen and advance the cursor. Unseen events are
+// returned in the order they occurred.
+func (c *eventCursor) advance(items []interface{}) []map[string]interface{} {
+	unseen := make([]map[string]interface{}, 0)
+	for _, item := range items {
+		fields, err := entityFields(item)
+		if err != nil {
+			continue
+		}
+		id := entityValue(fields, "id")
+		if _, ok := c.seen[id]; ok {
+			continue
+		}
+		occurred, err := time.Parse(time.RFC3339Nano, entityValue(fields, "occurredTime"))
+		if err != nil {
+			occurred = time.Now()
+		}
+		if occurred.Before(c.start()) {
+			continue
+		}
+		c.seen[id] = occurred
+		if occurred.After(c.position) {
+			c.position = occurred
+		}
+		if occurred.Before(c.from) {
+			continue
+		}
+		unseen = append(unseen, fields)
+	}
+	sort.SliceStable(unseen, func(i, j int) bool {
+		return entityValue(unseen[i], "occurredTime") < entityValue(unseen[j], "occurredTime")
+	})
+
+	// Forget events that can no longer be returned by a poll.
+	for id, occurred := range c.seen {
+		if occurred.Before(c.start()) {
+			delete(c.seen, id)
+		}
+	}
+	return unseen
+}
+
+// Prints events as they arrive in the selected output format.
+type eventPrinter struct {
+	w      io.Writer
+	csv    *csv.Writer
+	header bool
+}
+
+// Print a single event.
+func (p *eventPrinter) print(fields map[string]interface{}) error {
+	switch output.GetFormat() {
+	case output.FormatJson:
+		b, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.w, string(b))
+		return err
+	case output.FormatYaml:
+		b, err := yaml.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.w, "---\n%s", b)
+		return err
+	case output.FormatCsv:
+		table := (&EventList{Items: []interface{}{fields}}).Table()
+		if !p.header {
+			headers := make([]string, 0, len(table.Columns))
+			for _, col := range table.Columns {
+				headers = append(headers, col.Header)
+			}
+			if err := p.csv.Write(headers); err != nil {
+				return err
+			}
+			p.header = true
+		}
+		for _, row := range table.Rows {
+			if err := p.csv.Write(row); err != nil {
+				return err
+			}
+		}
+		p.csv.Flush()
+		return p.csv.Error()
+	}
+	line := fmt.Sprintf("%s %s %-20s %s", entityValue(fields, "occurredTime"),
+		color.HiCyanString("%-12s", entityValue(fields, "eventType")), entityValue(fields, "device.token"),
+		eventDetails(fields))
+	if output.GetFormat() == output.FormatWide {
+		line += fmt.Sprintf(" id=%s assignment=%s", entityValue(fields, "id"), entityValue(fields, "deviceAssignment.token"))
+	}
+	_, err := fmt.Fprintln(p.w, line)
+	return err
+}
+
+// Wait for the given duration or until the context is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Poll event management for new events until interrupted
+func tailEvents(cmd *cobra.Command) error {
+	since, _ := cmd.Flags().GetDuration("since")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	lookback, _ := cmd.Flags().GetDuration("lookback")
+	size, _ := cmd.Flags().GetInt("page-size")
+	if interval <= 0 || lookback < 0 || since < 0 {
+		return errors.New("interval must be greater than zero and since and lookback may not be negative")
+	}
+	if size < 1 {
+		return errors.New("page-size must be greater than zero")
+	}
+	criteria, err := eventCriteriaFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	criteria.PageSize = size
+	em, err := gql.NewEventManagementGraphQLClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Stop cleanly on Ctrl-C.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Events in the lookback window before the start are treated as already seen.
+	from := time.Now().Add(-since)
+	cursor := &eventCursor{from: from, position: from, lookback: lookback, seen: make(map[string]time.Time)}
+	if since == 0 {
+		if err := primeCursor(ctx, &em, criteria, cursor); err != nil {
+			fmt.Fprintf(output.Progress(), color.HiYellowString("Unable to query events: %v\n"), err)
+		}
+	}
+	printer := &eventPrinter{w: os.Stdout, csv: csv.NewWriter(os.Stdout)}
+	fmt.Fprintf(output.Progress(), "Waiting for events (press Ctrl-C to stop)...\n")
+
+	backoff := interval
+	connected := true
+	for {
+		polled := criteria
+		start := cursor.start()
+		polled.StartTime = &start
+		list, err := listAllEvents(ctx, &em, polled)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			if connected {
+				fmt.Fprintf(output.Progress(), color.HiYellowString("Lost connection to event management: %v\n"), err)
+				connected = false
+			}
+			backoff *= 2
+			if backoff > TAIL_MAX_BACKOFF {
+				backoff = TAIL_MAX_BACKOFF
+			}
+			fmt.Fprintf(output.Progress(), "Reconnecting in %s...\n", backoff)
+			if !sleepContext(ctx, backoff) {
+				return nil
+			}
+			continue
+		}
+		if !connected {
+			fmt.Fprintln(output.Progress(), color.HiGreenString("Reconnected to event management."))
+			connected = true
+			backoff = interval
+		}
+		for _, fields := range cursor.advance(list.Items) {
+			if err := printer.print(fields); err != nil {
+				return err
+			}
+		}
+		if !sleepContext(ctx, interval) {
+			return nil
+		}
+	}
+}
+
+// Mark events already stored in the lookback window as seen so only new events are shown.
+func primeCursor(ctx context.Context, em *gql.EventManagementClient, criteria gql.EventSearchCriteria, cursor *eventCursor) error {
+	start := cursor.start()
+	criteria.StartTime = &start
+	list, err := listAllEvents(ctx, em, criteria)
+	if err != nil {
+		return err
+	}
+	position := cursor.position
+	cursor.advance(list.Items)
+	cursor.position = position
+	return nil
+}
+
+func init() {
+	eventsCmd.AddCommand(eventsTailCmd)
+}
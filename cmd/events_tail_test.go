@@ -0,0 +1,118 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Time used as the start of event cursors in tests.
+var cursorBase = time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// Build an event that occurred the given number of seconds after the cursor base.
+func cursorEvent(id string, seconds int) interface{} {
+	return map[string]interface{}{
+		"id":           id,
+		"occurredTime": cursorBase.Add(time.Duration(seconds) * time.Second).Format(time.RFC3339Nano),
+	}
+}
+
+// Get the ids of events in order.
+func eventIds(events []map[string]interface{}) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, entityValue(event, "id"))
+	}
+	return ids
+}
+
+func TestEventCursorAdvance(t *testing.T) {
+	tests := []struct {
+		name         string
+		polls        [][]interface{}
+		want         [][]string
+		wantPosition int
+	}{
+		{
+			name:         "new events in order of occurrence",
+			polls:        [][]interface{}{{cursorEvent("b", 2), cursorEvent("a", 1), cursorEvent("c", 3)}},
+			want:         [][]string{{"a", "b", "c"}},
+			wantPosition: 3,
+		},
+		{
+			name: "events returned again are skipped",
+			polls: [][]interface{}{
+				{cursorEvent("a", 1), cursorEvent("b", 2)},
+				{cursorEvent("a", 1), cursorEvent("b", 2), cursorEvent("c", 3)},
+				{cursorEvent("c", 3)},
+			},
+			want:         [][]string{{"a", "b"}, {"c"}, {}},
+			wantPosition: 3,
+		},
+		{
+			name:         "duplicates within a poll are skipped",
+			polls:        [][]interface{}{{cursorEvent("a", 1), cursorEvent("a", 1)}},
+			want:         [][]string{{"a"}},
+			wantPosition: 1,
+		},
+		{
+			name: "late events within the lookback window are shown",
+			polls: [][]interface{}{
+				{cursorEvent("a", 10), cursorEvent("b", 20)},
+				{cursorEvent("late", 15), cursorEvent("a", 10), cursorEvent("b", 20)},
+			},
+			want:         [][]string{{"a", "b"}, {"late"}},
+			wantPosition: 20,
+		},
+		{
+			name: "events before the lookback window are skipped",
+			polls: [][]interface{}{
+				{cursorEvent("a", 100)},
+				{cursorEvent("old", 50), cursorEvent("b", 101)},
+			},
+			want:         [][]string{{"a"}, {"b"}},
+			wantPosition: 101,
+		},
+		{
+			name:         "events before the start are not shown",
+			polls:        [][]interface{}{{cursorEvent("before", -5), cursorEvent("a", 1)}},
+			want:         [][]string{{"a"}},
+			wantPosition: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := &eventCursor{from: cursorBase, position: cursorBase, lookback: 30 * time.Second,
+				seen: make(map[string]time.Time)}
+			for i, poll := range tt.polls {
+				if got := eventIds(cursor.advance(poll)); !reflect.DeepEqual(got, tt.want[i]) {
+					t.Errorf("poll %d returned %v, want %v", i+1, got, tt.want[i])
+				}
+			}
+			if want := cursorBase.Add(time.Duration(tt.wantPosition) * time.Second); !cursor.position.Equal(want) {
+				t.Errorf("position = %s, want %s", cursor.position, want)
+			}
+		})
+	}
+}
+
+func TestEventCursorForgetsOldEvents(t *testing.T) {
+	cursor := &eventCursor{from: cursorBase, position: cursorBase, lookback: 30 * time.Second,
+		seen: make(map[string]time.Time)}
+	cursor.advance([]interface{}{cursorEvent("a", 1), cursorEvent("b", 40)})
+	if _, ok := cursor.seen["a"]; ok {
+		t.Error("event outside the lookback window is still tracked")
+	}
+	if _, ok := cursor.seen["b"]; !ok {
+		t.Error("event inside the lookback window is not tracked")
+	}
+	if start := cursor.start(); !start.Equal(cursorBase.Add(10 * time.Second)) {
+		t.Errorf("start = %s", start)
+	}
+}
@@ -0,0 +1,36 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// Add flags used to target a DeviceChain instance and tenant for remote calls.
+func addTargetFlags(flags *pflag.FlagSet) {
+	flags.StringP("server", "s", "localhost", "server hostname targeted for remote calls")
+	addInstanceFlag(flags)
+	flags.StringP("tenant", "t", "tenant1", "tenant id targeted for remote calls")
+	addTransportFlags(flags)
+}
+
+// Add flag used to select the instance whose resources are targeted.
+func addInstanceFlag(flags *pflag.FlagSet) {
+	flags.StringP("instance", "i", "dc1", "instance id targeted for remote calls")
+}
+
+// Add flags used to configure the transport for remote calls.
+func addTransportFlags(flags *pflag.FlagSet) {
+	flags.String("scheme", "http", "scheme used for remote calls (http or https)")
+	flags.String("ca-file", "", "path to CA bundle used to verify server certificates")
+	flags.String("client-cert", "", "path to client certificate used for TLS authentication")
+	flags.String("client-key", "", "path to client key used for TLS authentication")
+	flags.Bool("insecure-skip-tls-verify", false, "skip verification of server certificates (insecure)")
+	flags.String("timeout", "30s", "timeout for each remote call")
+	flags.String("proxy", "", "url of HTTP proxy used for remote calls (defaults to environment)")
+	flags.Int("retries", 3, "number of retries for failed queries and idempotent operations")
+}
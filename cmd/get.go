@@ -0,0 +1,133 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/spf13/cobra"
+)
+
+// Create common command for displaying DeviceChain entities
+var getCmd = &cobra.Command{
+	Use:     "get",
+	Aliases: []string{"list"},
+	Short:   "Display one or many entities",
+	Long:    `Displays entities by token or lists entities a page at a time`,
+}
+
+// List of entities of a single kind.
+type EntityList struct {
+	Kind         string        `json:"kind"`
+	Items        []interface{} `json:"items"`
+	PageNumber   int           `json:"pageNumber,omitempty"`
+	PageSize     int           `json:"pageSize,omitempty"`
+	TotalRecords int           `json:"totalRecords"`
+	kind         *dmKind
+}
+
+// Render entities as a table.
+func (list *EntityList) Table() *output.Table {
+	return entityTable(list.kind.allColumns(), list.Items)
+}
+
+// Create command that will get entities of a device management kind
+func newGetDmKindCommand(kind *dmKind) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          fmt.Sprintf("%s [token...]", kind.Name),
+		Aliases:      []string{kind.Singular},
+		Short:        fmt.Sprintf("Display %ss", kind.Title),
+		Long:         fmt.Sprintf(`Displays %ss by token or lists them a page at a time`, kind.Title),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getDmEntities(cmd, kind, args)
+		}}
+	addTargetFlags(cmd.Flags())
+	cmd.Flags().Int("page", 1, "page number to display")
+	cmd.Flags().Int("page-size", 100, "number of entities per page")
+	cmd.Flags().Bool("all", false, "walk every page and display all entities")
+	return cmd
+}
+
+// List all pages of entities for a kind.
+func listAllDmEntities(ctx context.Context, dm *gql.DeviceManagementClient, kind *dmKind, size int) (*EntityList, error) {
+	items, err := listAllPages(func(page int) ([]interface{}, int, error) {
+		items, pagination, err := kind.listEntities(ctx, dm, page, size)
+		if err != nil || pagination == nil {
+			return items, -1, err
+		}
+		return items, pagination.GetTotalRecords(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &EntityList{Kind: kind.Name, Items: items, TotalRecords: len(items), kind: kind}, nil
+}
+
+// Get entities by token or list them
+func getDmEntities(cmd *cobra.Command, kind *dmKind, tokens []string) error {
+	page, _ := cmd.Flags().GetInt("page")
+	size, _ := cmd.Flags().GetInt("page-size")
+	all, _ := cmd.Flags().GetBool("all")
+	if page < 1 || size < 1 {
+		return errors.New("page and page-size must be greater than zero")
+	}
+	dm, err := gql.NewDeviceManagementGraphQLClient(cmd)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	// Look up specific entities by token.
+	if len(tokens) > 0 {
+		items, err := kind.getEntities(ctx, &dm, tokens)
+		if err != nil {
+			return err
+		}
+		return output.Print(&EntityList{Kind: kind.Name, Items: items, TotalRecords: len(items), kind: kind})
+	}
+
+	// Walk every page if requested.
+	if all {
+		list, err := listAllDmEntities(ctx, &dm, kind, size)
+		if err != nil {
+			return err
+		}
+		return output.Print(list)
+	}
+
+	items, pagination, err := kind.listEntities(ctx, &dm, page, size)
+	if err != nil {
+		return err
+	}
+	list := &EntityList{Kind: kind.Name, Items: items, PageNumber: page, PageSize: size, kind: kind}
+	if pagination != nil {
+		list.TotalRecords = pagination.GetTotalRecords()
+	}
+	err = output.Print(list)
+	if err != nil {
+		return err
+	}
+	if !output.Structured() && list.TotalRecords > page*size {
+		pages := (list.TotalRecords + size - 1) / size
+		fmt.Fprintf(output.Progress(), "\nShowing page %d of %d (%d %ss). Use --page or --all to see more.\n",
+			page, pages, list.TotalRecords, kind.Title)
+	}
+	return nil
+}
+
+func init() {
+	for _, kind := range dmKinds {
+		getCmd.AddCommand(newGetDmKindCommand(kind))
+	}
+
+	rootCmd.AddCommand(getCmd)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"testing"
+)
+
+func TestGetCommandFlags(t *testing.T) {
+	tests := []struct {
+		command  string
+		flags    []string
+		excluded []string
+	}{
+		{command: "devices", flags: []string{"page", "page-size", "all", "server", "tenant", "scheme", "retries"}},
+		{command: "instances", excluded: []string{"page", "page-size", "all", "server", "tenant", "scheme", "retries", "instance"}},
+		{command: "tenants", flags: []string{"instance", "all-instances"},
+			excluded: []string{"page", "page-size", "all", "server", "tenant", "scheme", "retries"}},
+		{command: "microservices", flags: []string{"instance", "all-instances"},
+			excluded: []string{"page", "page-size", "all", "server", "tenant", "scheme", "retries"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			cmd, _, err := getCmd.Find([]string{tt.command})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range tt.flags {
+				if cmd.Flag(name) == nil {
+					t.Errorf("missing flag --%s", name)
+				}
+			}
+			for _, name := range tt.excluded {
+				if cmd.Flag(name) != nil {
+					t.Errorf("unexpected flag --%s", name)
+				}
+			}
+		})
+	}
+}
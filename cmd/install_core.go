@@ -9,6 +9,7 @@ package cmd
 import (
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -20,8 +21,11 @@ import (
 	apply "github.com/devicechain-io/dc-k8s/apply"
 	dck8s "github.com/devicechain-io/dc-k8s/config"
 	gen "github.com/devicechain-io/dc-k8s/generators"
+	"github.com/devicechain-io/dcctl/output"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
@@ -44,47 +48,64 @@ func NewInstallCoreCommand() *cobra.Command {
 		Short: "Install core components",
 		Long:  `Installs Kubernetes manifests and operator`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("Preparing to install DeviceChain core components...")
+			fmt.Fprintln(output.Progress(), "Preparing to install DeviceChain core components...")
+			report := NewOperationReport("install core")
 			domain, _ := cmd.Flags().GetString("domain")
 			name, _ := cmd.Flags().GetString("name")
 			desc, _ := cmd.Flags().GetString("desc")
+			dryRun, _ := cmd.Flags().GetString("dry-run")
+			inline, _ := cmd.Flags().GetBool("inline-credentials")
 
 			dynamicClient, discoveryClient, err := createClients()
 			if err != nil {
 				return err
 			}
 
+			// Report what would change without modifying the cluster.
+			switch dryRun {
+			case DRY_RUN_NONE:
+			case DRY_RUN_CLIENT, DRY_RUN_SERVER:
+				return dryRunCore(newManifestClient(dynamicClient, discoveryClient), dryRun == DRY_RUN_SERVER, report)
+			default:
+				return fmt.Errorf("invalid dry run mode '%s' (expected %s, %s or %s)", dryRun, DRY_RUN_NONE,
+					DRY_RUN_CLIENT, DRY_RUN_SERVER)
+			}
+
 			// Make sure the system namespace exists.
-			err = assureSystemNamespace()
+			err = assureSystemNamespace(report)
 			if err != nil {
 				return err
 			}
 
 			// Install CRDs.
-			err = installCrds(dynamicClient, discoveryClient)
+			err = installCrds(dynamicClient, discoveryClient, report)
 			if err != nil {
 				return err
 			}
 
 			// Make sure the cluster resource exists.
-			err = assureClusterResource(domain, name, desc)
+			err = assureClusterResource(domain, name, desc, report)
 			if err != nil {
 				return err
 			}
 
 			// Install RBAC files.
-			err = installRbac(dynamicClient, discoveryClient)
+			err = installRbac(dynamicClient, discoveryClient, report)
 			if err != nil {
 				return err
 			}
 
 			// Install operator files.
-			err = installOperator(dynamicClient, discoveryClient)
+			err = installOperator(dynamicClient, discoveryClient, report)
 			if err != nil {
 				return err
 			}
 
-			fmt.Println(GreenUnderline("\nInstall Custom Resources"))
+			fmt.Fprintln(output.Progress(), GreenUnderline("\nInstall Custom Resources"))
+			if inline {
+				fmt.Fprintln(output.Progress(), color.HiYellowString("Database passwords will be embedded in instance "+
+					"configurations and are readable by anyone with access to them."))
+			}
 			err = filepath.Walk(GenResFolder, func(path string, info os.FileInfo, err error) error {
 				if err != nil {
 					return err
@@ -97,25 +118,124 @@ func NewInstallCoreCommand() *cobra.Command {
 					return err
 				}
 
+				// Reference the secrets created by install infra unless passwords are to be inlined.
+				if inline {
+					b, err = inlineResourceYaml(context.Background(), path, b)
+				} else {
+					b, err = secureResourceYaml(path, b)
+				}
+				if err != nil {
+					return err
+				}
+
 				err = applyYaml(dynamicClient, discoveryClient, b)
 				if err != nil {
 					return err
 				}
 
-				fmt.Printf(color.WhiteString("Installed resource: %s\n"), color.GreenString(path))
+				fmt.Fprintf(output.Progress(), color.WhiteString("Installed resource: %s\n"), color.GreenString(path))
+				report.Step("resource", path, "applied")
 				return nil
 			})
-			if err != nil {
-				fmt.Println(err)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
 			}
-			fmt.Println(color.HiGreenString("\nInstallation completed successfully."))
-			return nil
+
+			// Wait for the operator to become ready if requested.
+			if wait, _ := cmd.Flags().GetBool("wait"); wait {
+				timeout, _ := cmd.Flags().GetDuration("timeout")
+				err = waitForComponents(coreComponentChecks, timeout, report)
+				if err != nil {
+					return err
+				}
+			}
+			fmt.Fprintln(output.Progress(), color.HiGreenString("\nInstallation completed successfully."))
+			return report.Complete()
 		},
 	}
 }
 
+// Report how install core would change the cluster. Client dry runs only check whether objects
+// exist. Server dry runs also have the server validate each object and detect unchanged objects.
+func dryRunCore(mc *manifestClient, server bool, report *OperationReport) error {
+	ctx := context.Background()
+	mode := DRY_RUN_CLIENT
+	if server {
+		mode = DRY_RUN_SERVER
+	}
+	fmt.Fprintln(output.Progress(), GreenUnderline(fmt.Sprintf("\nInstall Core Components (dry run: %s)", mode)))
+
+	ns := &corev1.Namespace{}
+	status := "verified"
+	if err := v1beta1.V1Client.Get(ctx, types.NamespacedName{Name: NS_DC_SYSTEM}, ns); err != nil {
+		status = "created"
+	}
+	fmt.Fprintf(output.Progress(), "Namespace %s %s (dry run)\n", NS_DC_SYSTEM, color.GreenString(status))
+	report.Step("namespace", NS_DC_SYSTEM, status)
+
+	cluster := &v1beta1.Cluster{}
+	status = "verified"
+	if err := v1beta1.V1Beta1Client.Get(ctx, types.NamespacedName{Name: CLUSTER_NAME}, cluster); err != nil {
+		status = "created"
+	}
+	fmt.Fprintf(output.Progress(), "Cluster %s %s (dry run)\n", CLUSTER_NAME, color.GreenString(status))
+	report.Step("cluster", CLUSTER_NAME, status)
+
+	manifests, err := loadCoreManifests()
+	if err != nil {
+		return err
+	}
+	for _, manifest := range manifests {
+		status, err := dryRunStatus(ctx, mc, manifest, server)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(output.Progress(), "%s %s (dry run)\n", manifest, color.GreenString(status))
+		report.Step(manifest.Group, manifest.String(), status)
+	}
+	fmt.Fprintln(output.Progress(), color.HiGreenString("\nDry run completed. No changes were made."))
+	return report.Complete()
+}
+
+// Determine how applying a manifest object would change the cluster.
+func dryRunStatus(ctx context.Context, mc *manifestClient, manifest *coreManifest, server bool) (string, error) {
+	live, err := mc.live(ctx, manifest.Object)
+	if meta.IsNoMatchError(err) {
+		// Kinds defined by CRDs that are not installed yet can not be checked.
+		return "created (unverified)", nil
+	} else if err != nil {
+		return "", fmt.Errorf("unable to get %s: %v", manifest, err)
+	}
+	if !server {
+		if live == nil {
+			return "created", nil
+		}
+		return "configured", nil
+	}
+
+	desired, err := mc.desired(ctx, manifest.Object, true)
+	if err != nil {
+		return "", fmt.Errorf("dry run failed for %s from '%s': %v", manifest, manifest.Source, err)
+	}
+	if live == nil {
+		return "created", nil
+	}
+	before, err := normalizedYaml(live)
+	if err != nil {
+		return "", err
+	}
+	after, err := normalizedYaml(desired)
+	if err != nil {
+		return "", err
+	}
+	if before == after {
+		return "unchanged", nil
+	}
+	return "configured", nil
+}
+
 // Assure that a cluster resource exists.
-func assureClusterResource(domain string, name string, desc string) error {
+func assureClusterResource(domain string, name string, desc string, report *OperationReport) error {
 	if domain == "" {
 		domain = "mydc.com"
 	}
@@ -127,7 +247,7 @@ func assureClusterResource(domain string, name string, desc string) error {
 	}
 
 	// Check for existing namespace.
-	fmt.Print(color.WhiteString("\nVerifying cluster resource... "))
+	fmt.Fprint(output.Progress(), color.WhiteString("\nVerifying cluster resource... "))
 	cluster := &v1beta1.Cluster{}
 	err := v1beta1.V1Beta1Client.Get(context.Background(), types.NamespacedName{Name: CLUSTER_NAME}, cluster)
 	if err != nil {
@@ -143,16 +263,18 @@ func assureClusterResource(domain string, name string, desc string) error {
 			},
 		}
 		err = v1beta1.V1Beta1Client.Create(context.Background(), cluster)
-		fmt.Println(color.GreenString("Created cluster resource."))
+		fmt.Fprintln(output.Progress(), color.GreenString("Created cluster resource."))
+		report.Step("cluster", CLUSTER_NAME, "created")
 	} else {
-		fmt.Println(color.GreenString("Cluster resource verified."))
+		fmt.Fprintln(output.Progress(), color.GreenString("Cluster resource verified."))
+		report.Step("cluster", CLUSTER_NAME, "verified")
 	}
 	return err
 }
 
 // Install all custom resource definitions from k8s metadata.
-func installCrds(dynamicClient dynamic.Interface, discoveryClient *discovery.DiscoveryClient) error {
-	fmt.Println(GreenUnderline("\nInstall Custom Resource Definitions"))
+func installCrds(dynamicClient dynamic.Interface, discoveryClient *discovery.DiscoveryClient, report *OperationReport) error {
+	fmt.Fprintln(output.Progress(), GreenUnderline("\nInstall Custom Resource Definitions"))
 	crdfiles := dck8s.CrdFiles()
 	crds, err := getEmbeddedContent(crdfiles, "crd/bases")
 	if err != nil {
@@ -164,15 +286,16 @@ func installCrds(dynamicClient dynamic.Interface, discoveryClient *discovery.Dis
 			return err
 		}
 
-		fmt.Printf(color.WhiteString("Installed CRD: %s\n"),
+		fmt.Fprintf(output.Progress(), color.WhiteString("Installed CRD: %s\n"),
 			color.GreenString(strings.TrimPrefix(current.Name, "crd/bases/")))
+		report.Step("crd", strings.TrimPrefix(current.Name, "crd/bases/"), "applied")
 	}
 	return nil
 }
 
 // Install all RBAC definitions from k8s metadata.
-func installRbac(dynamicClient dynamic.Interface, discoveryClient *discovery.DiscoveryClient) error {
-	fmt.Println(GreenUnderline("\nInstall RBAC Components"))
+func installRbac(dynamicClient dynamic.Interface, discoveryClient *discovery.DiscoveryClient, report *OperationReport) error {
+	fmt.Fprintln(output.Progress(), GreenUnderline("\nInstall RBAC Components"))
 	crdfiles := dck8s.RbacFiles()
 	crds, err := getEmbeddedContent(crdfiles, "rbac")
 	if err != nil {
@@ -184,15 +307,16 @@ func installRbac(dynamicClient dynamic.Interface, discoveryClient *discovery.Dis
 			return err
 		}
 
-		fmt.Printf(color.WhiteString("Installed RBAC: %s\n"),
+		fmt.Fprintf(output.Progress(), color.WhiteString("Installed RBAC: %s\n"),
 			color.GreenString(strings.TrimPrefix(current.Name, "rbac/")))
+		report.Step("rbac", strings.TrimPrefix(current.Name, "rbac/"), "applied")
 	}
 	return nil
 }
 
 // Install all operator definitions from k8s metadata.
-func installOperator(dynamicClient dynamic.Interface, discoveryClient *discovery.DiscoveryClient) error {
-	fmt.Println(GreenUnderline("\nInstall Operator Components"))
+func installOperator(dynamicClient dynamic.Interface, discoveryClient *discovery.DiscoveryClient, report *OperationReport) error {
+	fmt.Fprintln(output.Progress(), GreenUnderline("\nInstall Operator Components"))
 	mgrfiles := dck8s.ManagerFiles()
 	mgrs, err := getEmbeddedContent(mgrfiles, "manager")
 	if err != nil {
@@ -204,8 +328,9 @@ func installOperator(dynamicClient dynamic.Interface, discoveryClient *discovery
 			return err
 		}
 
-		fmt.Printf(color.WhiteString("Installed Operator Component: %s\n"),
+		fmt.Fprintf(output.Progress(), color.WhiteString("Installed Operator Component: %s\n"),
 			color.GreenString(strings.TrimPrefix(current.Name, "manager/")))
+		report.Step("operator", strings.TrimPrefix(current.Name, "manager/"), "applied")
 	}
 	return nil
 }
@@ -275,4 +400,10 @@ func init() {
 	installCoreCmd.Flags().StringP("domain", "s", "mydc.com", "Domain suffix used to filter ingress")
 	installCoreCmd.Flags().StringP("name", "n", "", "Specifies human-readable name for instance")
 	installCoreCmd.Flags().StringP("desc", "d", "", "Specifies human-readable description for instance")
+	installCoreCmd.Flags().String("dry-run", DRY_RUN_NONE, fmt.Sprintf("report changes without applying them (%s|%s|%s)",
+		DRY_RUN_NONE, DRY_RUN_CLIENT, DRY_RUN_SERVER))
+	installCoreCmd.Flags().Lookup("dry-run").NoOptDefVal = DRY_RUN_CLIENT
+	installCoreCmd.Flags().Bool("inline-credentials", false,
+		"embed database passwords in instance configurations (for operators that do not resolve passwordSecretRef)")
+	addInstallWaitFlags(installCoreCmd)
 }
@@ -16,8 +16,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	corev1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/devicechain-io/dcctl/output"
 	"github.com/fatih/color"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -47,9 +50,6 @@ var (
 	//go:embed install_infra/charts/*
 	ChartFS embed.FS
 
-	//go:embed install_infra/preinstall/*
-	PreinstallFS embed.FS
-
 	//go:embed install_infra/resources/*
 	ResourcesFS embed.FS
 )
@@ -59,20 +59,52 @@ var installInfraCmd = NewInstallInfraCommand()
 
 // Create command for installing DeviceChain infrastructure
 func NewInstallInfraCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:          "infra",
-		Short:        "Install infrastructure components",
-		Long:         `Installs and configures DeviceChain infrastructure dependencies`,
+	cmd := &cobra.Command{
+		Use:   "infra",
+		Short: "Install infrastructure components",
+		Long: `Installs and configures DeviceChain infrastructure dependencies. Credentials are generated
+on the first install and stored in secrets in the dc-system namespace. Values for the
+embedded Helm charts may be changed with values files or individual values scoped to a
+chart. They are merged on top of the values dcctl uses by default.`,
+		Example: `  dcctl install infra --set postgresql.primary.persistence.size=20Gi --set redis.architecture=standalone
+  dcctl install infra -f postgresql=pg-values.yaml -f redis=redis-values.yaml`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return installInfraComponents()
+			userValues, err := chartValuesFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			settings := helmSettings(cmd)
+			err = checkCredentialValues(userValues, getter.All(settings))
+			if err != nil {
+				return err
+			}
+			wait, _ := cmd.Flags().GetBool("wait")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			return installInfraComponents(settings, userValues, wait, timeout)
 		},
 	}
+	addChartValuesFlags(cmd)
+	addInstallWaitFlags(cmd)
+	return cmd
+}
+
+// Get Helm settings targeting the same cluster as the Kubernetes clients of a command.
+func helmSettings(cmd *cobra.Command) *cli.EnvSettings {
+	settings := cli.New()
+	if kubeconfig := config.Kubeconfig.Resolve(cmd.Flags()); kubeconfig != "" {
+		settings.KubeConfig = kubeconfig
+	}
+	if kubecontext := config.KubeContext.Resolve(cmd.Flags()); kubecontext != "" {
+		settings.KubeContext = kubecontext
+	}
+	return settings
 }
 
 // Install all infrastructure components
-func installInfraComponents() error {
-	fmt.Println("Preparing to install DeviceChain infrastructure components...")
+func installInfraComponents(settings *cli.EnvSettings, userValues chartValues, wait bool, timeout time.Duration) error {
+	fmt.Fprintln(output.Progress(), "Preparing to install DeviceChain infrastructure components...")
+	report := NewOperationReport("install infra")
 
 	dynamicClient, discoveryClient, err := createClients()
 	if err != nil {
@@ -80,13 +112,12 @@ func installInfraComponents() error {
 	}
 
 	// Validate that system namespace exists.
-	err = assureSystemNamespace()
+	err = assureSystemNamespace(report)
 	if err != nil {
 		return err
 	}
 
 	// Locate and/or setup Helm repositories.
-	settings := cli.New()
 	rfile, err := assureHelmRepositoryConfig(settings)
 	if err != nil && !os.IsExist(err) {
 		return err
@@ -107,46 +138,56 @@ func installInfraComponents() error {
 			URL:  "https://k8s-at-home.com/charts/",
 		},
 	}
-	err = addHelmRepositories(entries, settings, rfile)
+	err = addHelmRepositories(entries, settings, rfile, report)
 	if err != nil {
 		return err
 	}
 
-	// Preinstall k8s resources from embedded yaml files.
-	err = createPreinstallResources(dynamicClient, discoveryClient)
+	// Generate credentials used by charts if they do not already exist.
+	creds, err := assureInfraSecrets(context.Background(), report)
 	if err != nil {
 		return err
 	}
 
 	// Create Helm releases from embedded charts.
-	err = createHelmReleases(settings)
+	err = createHelmReleases(settings, creds, userValues, report)
 	if err != nil {
 		return err
 	}
 
 	// Create k8s resources from embedded yaml files.
-	err = createInfraResources(dynamicClient, discoveryClient)
+	err = createInfraResources(dynamicClient, discoveryClient, report)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(color.HiGreenString("\nInstallation completed successfully."))
-	return nil
+	// Wait for charts and the Kafka cluster to become ready if requested.
+	if wait {
+		err = waitForComponents(infraComponentChecks(settings), timeout, report)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(output.Progress(), color.HiGreenString("\nInstallation completed successfully."))
+	return report.Complete()
 }
 
-// Assure that
-func assureSystemNamespace() error {
+// Assure that the system namespace exists.
+func assureSystemNamespace(report *OperationReport) error {
 	// Check for existing namespace.
-	fmt.Print(color.WhiteString("\nVerifying DeviceChain system namespace... "))
+	fmt.Fprint(output.Progress(), color.WhiteString("\nVerifying DeviceChain system namespace... "))
 	ns := &corev1.Namespace{}
 	err := corev1beta1.V1Client.Get(context.Background(), types.NamespacedName{Name: NS_DC_SYSTEM}, ns)
 	if err != nil {
 		// Attempt to create the namespace.
 		ns = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: NS_DC_SYSTEM}}
 		err = corev1beta1.V1Client.Create(context.Background(), ns)
-		fmt.Println(color.GreenString("Created system namespace."))
+		fmt.Fprintln(output.Progress(), color.GreenString("Created system namespace."))
+		report.Step("namespace", NS_DC_SYSTEM, "created")
 	} else {
-		fmt.Println(color.GreenString("System namespace verified."))
+		fmt.Fprintln(output.Progress(), color.GreenString("System namespace verified."))
+		report.Step("namespace", NS_DC_SYSTEM, "verified")
 	}
 	return err
 }
@@ -167,25 +208,26 @@ func assureHelmRepositoryConfig(settings *cli.EnvSettings) (*repo.File, error) {
 		if err != nil {
 			return nil, err
 		}
-		fmt.Println(color.WhiteString("Created new Helm repositories configuration at: "),
+		fmt.Fprintln(output.Progress(), color.WhiteString("Created new Helm repositories configuration at: "),
 			color.GreenString(settings.RepositoryConfig))
 	} else {
 		file, err = repo.LoadFile(settings.RepositoryConfig)
 		if err != nil {
 			return nil, err
 		}
-		fmt.Println(color.WhiteString("Using existing Helm repositories configuration at: "),
+		fmt.Fprintln(output.Progress(), color.WhiteString("Using existing Helm repositories configuration at: "),
 			color.GreenString(settings.RepositoryConfig))
 	}
 	return file, nil
 }
 
 // Add Helm repository to configuration
-func addHelmRepositories(entries []*repo.Entry, settings *cli.EnvSettings, rfile *repo.File) error {
+func addHelmRepositories(entries []*repo.Entry, settings *cli.EnvSettings, rfile *repo.File, report *OperationReport) error {
 	for _, entry := range entries {
-		fmt.Printf(color.WhiteString("Checking repository '%s' ... "), entry.Name)
+		fmt.Fprintf(output.Progress(), color.WhiteString("Checking repository '%s' ... "), entry.Name)
 		if rfile.Has(entry.Name) {
-			fmt.Println(color.GreenString("FOUND"))
+			fmt.Fprintln(output.Progress(), color.GreenString("FOUND"))
+			report.Step("helm repository", entry.Name, "found")
 		} else {
 			// Pull index file to verify..
 			r, err := repo.NewChartRepository(entry, getter.All(settings))
@@ -203,7 +245,8 @@ func addHelmRepositories(entries []*repo.Entry, settings *cli.EnvSettings, rfile
 			if err != nil {
 				return err
 			}
-			fmt.Println(color.GreenString("ADDED"))
+			fmt.Fprintln(output.Progress(), color.GreenString("ADDED"))
+			report.Step("helm repository", entry.Name, "added")
 		}
 	}
 	return nil
@@ -211,7 +254,7 @@ func addHelmRepositories(entries []*repo.Entry, settings *cli.EnvSettings, rfile
 
 // Log output used for helm debugging.
 func helmDebug(format string, v ...interface{}) {
-	fmt.Println(color.WhiteString(fmt.Sprintf(format, v...)))
+	fmt.Fprintln(output.Progress(), color.WhiteString(fmt.Sprintf(format, v...)))
 }
 
 type ChartInfo struct {
@@ -231,7 +274,8 @@ func isChartInstallable(ch *chart.Chart) (bool, error) {
 }
 
 // Create a release for a Helm chart.
-func createHelmRelease(settings *cli.EnvSettings, chart *ChartInfo, overrides []string) (*release.Release, error) {
+func createHelmRelease(settings *cli.EnvSettings, chart *ChartInfo, overrides []string,
+	supplied *values.Options) (*release.Release, error) {
 	actionConfig := new(action.Configuration)
 	if err := actionConfig.Init(settings.RESTClientGetter(), NS_DC_SYSTEM, os.Getenv("HELM_DRIVER"), helmDebug); err != nil {
 		return nil, err
@@ -259,6 +303,10 @@ func createHelmRelease(settings *cli.EnvSettings, chart *ChartInfo, overrides []
 	if err != nil {
 		return nil, err
 	}
+	vals, err = mergeChartValues(vals, supplied, p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid values for chart '%s': %v", chart.Chart, err)
+	}
 
 	// Load chart from established path.
 	chartRequested, err := loader.Load(cp)
@@ -277,7 +325,7 @@ func createHelmRelease(settings *cli.EnvSettings, chart *ChartInfo, overrides []
 		if err := action.CheckDependencies(chartRequested, req); err != nil {
 			if installAction.DependencyUpdate {
 				man := &downloader.Manager{
-					Out:              os.Stdout,
+					Out:              output.Progress(),
 					ChartPath:        cp,
 					Keyring:          installAction.ChartPathOptions.Keyring,
 					SkipUpdate:       false,
@@ -329,8 +377,9 @@ func parseChartInfo(d fs.DirEntry) (*ChartInfo, error) {
 }
 
 // Create Helm releases for each chart embedded in the binary.
-func createHelmReleases(settings *cli.EnvSettings) error {
-	fmt.Println(GreenUnderline("\nInstall Helm Charts"))
+func createHelmReleases(settings *cli.EnvSettings, creds infraCredentials, userValues chartValues,
+	report *OperationReport) error {
+	fmt.Fprintln(output.Progress(), GreenUnderline("\nInstall Helm Charts"))
 	return fs.WalkDir(ChartFS, "install_infra/charts", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -340,7 +389,7 @@ func createHelmReleases(settings *cli.EnvSettings) error {
 			if err != nil {
 				return err
 			}
-			fmt.Printf("Installing Helm Chart: Repository: %s Chart: %s Version: %s Release: %s\n",
+			fmt.Fprintf(output.Progress(), "Installing Helm Chart: Repository: %s Chart: %s Version: %s Release: %s\n",
 				color.GreenString(cinfo.Repository),
 				color.GreenString(cinfo.Chart),
 				color.GreenString(cinfo.Version),
@@ -361,51 +410,23 @@ func createHelmReleases(settings *cli.EnvSettings) error {
 			for _, line := range lines {
 				overrides = append(overrides, strings.TrimSpace(line))
 			}
+			overrides = append(overrides, credentialOverrides(cinfo.Chart, creds)...)
 
 			uninstallHelmRelease(settings, cinfo)
-			_, err = createHelmRelease(settings, cinfo, overrides)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
-	})
-}
-
-// Preinstall (before helm charts) k8s resources for each yaml file embedded in the binary.
-func createPreinstallResources(dynamicClient dynamic.Interface, discoveryClient *discovery.DiscoveryClient) error {
-	fmt.Println(GreenUnderline("\nPreinstall Infra Resources"))
-	caser := cases.Title(language.Und)
-	return fs.WalkDir(PreinstallFS, "install_infra/preinstall", func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if !d.IsDir() {
-			parts := strings.Split(strings.TrimSuffix(d.Name(), ".yaml"), "_")
-			pname := caser.String(strings.ReplaceAll(strings.ToLower(parts[1]), "-", " "))
-			fmt.Printf("Preinstalling Yaml Resource: %s\n", color.GreenString(pname))
-
-			file, err := PreinstallFS.Open(path)
-			if err != nil {
-				return err
-			}
-			bytes, err := io.ReadAll(file)
-			if err != nil {
-				return err
-			}
-
-			err = applyYaml(dynamicClient, discoveryClient, bytes)
+			_, err = createHelmRelease(settings, cinfo, overrides, userValues[cinfo.Chart])
 			if err != nil {
 				return err
 			}
+			report.Step("helm release", cinfo.Release, "installed")
 		}
 		return nil
 	})
 }
 
 // Create k8s resources for each yaml file embedded in the binary.
-func createInfraResources(dynamicClient dynamic.Interface, discoveryClient *discovery.DiscoveryClient) error {
-	fmt.Println(GreenUnderline("\nInstall Infra Resources"))
+func createInfraResources(dynamicClient dynamic.Interface, discoveryClient *discovery.DiscoveryClient,
+	report *OperationReport) error {
+	fmt.Fprintln(output.Progress(), GreenUnderline("\nInstall Infra Resources"))
 	caser := cases.Title(language.Und)
 	return fs.WalkDir(ResourcesFS, "install_infra/resources", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -414,7 +435,7 @@ func createInfraResources(dynamicClient dynamic.Interface, discoveryClient *disc
 		if !d.IsDir() {
 			parts := strings.Split(strings.TrimSuffix(d.Name(), ".yaml"), "_")
 			pname := caser.String(strings.ReplaceAll(strings.ToLower(parts[1]), "-", " "))
-			fmt.Printf("Installing Yaml Resource: %s\n", color.GreenString(pname))
+			fmt.Fprintf(output.Progress(), "Installing Yaml Resource: %s\n", color.GreenString(pname))
 
 			file, err := ResourcesFS.Open(path)
 			if err != nil {
@@ -429,6 +450,7 @@ func createInfraResources(dynamicClient dynamic.Interface, discoveryClient *disc
 			if err != nil {
 				return err
 			}
+			report.Step("resource", pname, "applied")
 		}
 		return nil
 	})
@@ -0,0 +1,380 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/getter"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Secret holding generated credentials for infrastructure components.
+	INFRA_CREDENTIALS_SECRET = "dc-infra-credentials"
+
+	// Secret holding credentials read by the TimescaleDB chart.
+	TIMESCALE_CREDENTIALS_SECRET = "dc-timescaledb-single-credentials"
+
+	// Keys of generated credentials.
+	POSTGRESQL_PASSWORD_KEY          = "postgresql-password"
+	KEYCLOAK_ADMIN_PASSWORD_KEY      = "keycloak-admin-password"
+	TIMESCALE_SUPERUSER_PASSWORD_KEY = "PATRONI_SUPERUSER_PASSWORD"
+
+	// Length of generated passwords.
+	GENERATED_PASSWORD_LENGTH = 32
+
+	// Password used by installs made before credentials were generated.
+	LEGACY_PASSWORD = "devicechain"
+
+	// Value shown in place of passwords in CLI output.
+	REDACTED_PASSWORD = "<redacted>"
+)
+
+// Characters used in generated passwords. Kept alphanumeric so that passwords can be passed
+// as Helm values without escaping.
+const passwordCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Secrets created by install infra and the keys generated for each. If the secret is missing but
+// volumes of the release already exist, data was created with the legacy password and it is kept.
+// The TimescaleDB secret was created by earlier installs, so only missing keys are generated.
+var infraSecrets = []struct {
+	name    string
+	keys    []string
+	release string
+}{
+	{name: INFRA_CREDENTIALS_SECRET, keys: []string{POSTGRESQL_PASSWORD_KEY, KEYCLOAK_ADMIN_PASSWORD_KEY},
+		release: "dc-postgresql"},
+	{name: TIMESCALE_CREDENTIALS_SECRET, keys: []string{TIMESCALE_SUPERUSER_PASSWORD_KEY, "PATRONI_REPLICATION_PASSWORD",
+		"PATRONI_admin_PASSWORD"}},
+}
+
+// Secrets referenced by persistence configuration in place of plaintext passwords.
+var persistenceSecretRefs = map[string]corev1.SecretKeySelector{
+	"Rdb": {
+		LocalObjectReference: corev1.LocalObjectReference{Name: INFRA_CREDENTIALS_SECRET},
+		Key:                  POSTGRESQL_PASSWORD_KEY,
+	},
+	"Tsdb": {
+		LocalObjectReference: corev1.LocalObjectReference{Name: TIMESCALE_CREDENTIALS_SECRET},
+		Key:                  TIMESCALE_SUPERUSER_PASSWORD_KEY,
+	},
+}
+
+// Credentials stored in infrastructure secrets, keyed by secret name and then key.
+type infraCredentials map[string]map[string]string
+
+// Generate a random password.
+func generatePassword() (string, error) {
+	max := big.NewInt(int64(len(passwordCharacters)))
+	password := make([]byte, GENERATED_PASSWORD_LENGTH)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		password[i] = passwordCharacters[n.Int64()]
+	}
+	return string(password), nil
+}
+
+// Check whether persistent volume claims created by a Helm release exist.
+func hasReleaseData(ctx context.Context, release string) (bool, error) {
+	claims := &corev1.PersistentVolumeClaimList{}
+	err := v1beta1.V1Client.List(ctx, claims, client.InNamespace(NS_DC_SYSTEM),
+		client.MatchingLabels{"app.kubernetes.io/instance": release})
+	if err != nil {
+		return false, fmt.Errorf("unable to list volume claims for release '%s': %v", release, err)
+	}
+	return len(claims.Items) > 0, nil
+}
+
+// Assure that secrets holding infrastructure credentials exist. Credentials are only generated
+// for keys that are missing. Installs made before credentials were generated keep the legacy
+// password, since their databases were initialized with it.
+func assureInfraSecrets(ctx context.Context, report *OperationReport) (infraCredentials, error) {
+	fmt.Fprintln(output.Progress(), GreenUnderline("\nInfra Credentials"))
+	creds := make(infraCredentials)
+	for _, spec := range infraSecrets {
+		secret := &corev1.Secret{}
+		err := v1beta1.V1Client.Get(ctx, types.NamespacedName{Namespace: NS_DC_SYSTEM, Name: spec.name}, secret)
+		exists := true
+		legacy := false
+		if apierrors.IsNotFound(err) {
+			exists = false
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: NS_DC_SYSTEM, Name: spec.name},
+				Type:       corev1.SecretTypeOpaque,
+			}
+			if spec.release != "" {
+				legacy, err = hasReleaseData(ctx, spec.release)
+				if err != nil {
+					return nil, err
+				}
+			}
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to get secret '%s': %v", spec.name, err)
+		}
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		if legacy {
+			for _, key := range spec.keys {
+				secret.Data[key] = []byte(LEGACY_PASSWORD)
+			}
+		}
+
+		// Generate any credentials that are missing.
+		generated := make([]string, 0)
+		values := make(map[string]string)
+		for _, key := range spec.keys {
+			if len(secret.Data[key]) == 0 {
+				password, err := generatePassword()
+				if err != nil {
+					return nil, err
+				}
+				secret.Data[key] = []byte(password)
+				generated = append(generated, key)
+			}
+			values[key] = string(secret.Data[key])
+		}
+		creds[spec.name] = values
+
+		switch {
+		case legacy:
+			err = v1beta1.V1Client.Create(ctx, secret)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create secret '%s': %v", spec.name, err)
+			}
+			fmt.Fprintf(output.Progress(), color.HiYellowString("Existing data found for release '%s'. Stored its previous "+
+				"credentials in secret: %s\n"), spec.release, spec.name)
+			report.Step("secret", spec.name, "migrated")
+		case !exists:
+			err = v1beta1.V1Client.Create(ctx, secret)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create secret '%s': %v", spec.name, err)
+			}
+			fmt.Fprintf(output.Progress(), color.WhiteString("Generated credentials in secret: %s\n"), color.GreenString(spec.name))
+			report.Step("secret", spec.name, "created")
+		case len(generated) > 0:
+			err = v1beta1.V1Client.Update(ctx, secret)
+			if err != nil {
+				return nil, fmt.Errorf("unable to update secret '%s': %v", spec.name, err)
+			}
+			fmt.Fprintf(output.Progress(), color.WhiteString("Generated missing credentials (%s) in secret: %s\n"),
+				strings.Join(generated, ", "), color.GreenString(spec.name))
+			report.Step("secret", spec.name, "updated")
+		default:
+			fmt.Fprintf(output.Progress(), color.WhiteString("Using existing credentials in secret: %s\n"), color.GreenString(spec.name))
+			report.Step("secret", spec.name, "verified")
+		}
+	}
+	return creds, nil
+}
+
+// Chart value set from a credential stored in a secret.
+type chartCredential struct {
+	value  string
+	secret string
+	key    string
+}
+
+// Chart values that are set from generated credentials, keyed by chart name.
+var chartCredentials = map[string][]chartCredential{
+	"postgresql": {
+		{value: "auth.password", secret: INFRA_CREDENTIALS_SECRET, key: POSTGRESQL_PASSWORD_KEY},
+	},
+	"keycloak": {
+		{value: "auth.adminPassword", secret: INFRA_CREDENTIALS_SECRET, key: KEYCLOAK_ADMIN_PASSWORD_KEY},
+		{value: "externalDatabase.password", secret: INFRA_CREDENTIALS_SECRET, key: POSTGRESQL_PASSWORD_KEY},
+	},
+}
+
+// Get Helm overrides that pass generated credentials to a chart.
+func credentialOverrides(chart string, creds infraCredentials) []string {
+	overrides := make([]string, 0)
+	for _, cred := range chartCredentials[chart] {
+		overrides = append(overrides, fmt.Sprintf("%s=%s", cred.value, creds[cred.secret][cred.key]))
+	}
+	return overrides
+}
+
+// Check that values supplied for charts do not override credentials. Overridden credentials would
+// no longer match the secrets that microservices use to connect.
+func checkCredentialValues(userValues chartValues, p getter.Providers) error {
+	for chart, opts := range userValues {
+		vals, err := opts.MergeValues(p)
+		if err != nil {
+			return fmt.Errorf("invalid values for chart '%s': %v", chart, err)
+		}
+		for _, cred := range chartCredentials[chart] {
+			if _, err := chartutil.Values(vals).PathValue(cred.value); err == nil {
+				return fmt.Errorf("value '%s' for chart '%s' is generated by dcctl and can not be overridden "+
+					"(change key '%s' of secret '%s' in namespace %s instead)", cred.value, chart, cred.key, cred.secret,
+					NS_DC_SYSTEM)
+			}
+		}
+	}
+	return nil
+}
+
+// Replace plaintext passwords in the persistence configuration of an instance configuration with
+// references to the secrets created by install infra. Returns true if the object was changed.
+func referenceInfraSecrets(object *unstructured.Unstructured) (bool, error) {
+	if object.GetKind() != "InstanceConfiguration" {
+		return false, nil
+	}
+	changed := false
+	for store, ref := range persistenceSecretRefs {
+		path := []string{"spec", "configuration", "Persistence", store, "Configuration"}
+		config, found, err := unstructured.NestedMap(object.Object, path...)
+		if err != nil {
+			return false, fmt.Errorf("invalid %s configuration in '%s': %v", store, object.GetName(), err)
+		}
+		if !found {
+			continue
+		}
+		if _, ok := config["password"]; !ok {
+			continue
+		}
+		delete(config, "password")
+		config["passwordSecretRef"] = map[string]interface{}{
+			"name": ref.Name,
+			"key":  ref.Key,
+		}
+		err = unstructured.SetNestedMap(object.Object, config, path...)
+		if err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// Replace secret references in the persistence configuration of an instance configuration with
+// the passwords stored in the secrets. Only used for operators that can not resolve references
+// themselves. Returns true if the object was changed.
+func resolveInfraSecrets(ctx context.Context, object *unstructured.Unstructured) (bool, error) {
+	if object.GetKind() != "InstanceConfiguration" {
+		return false, nil
+	}
+	changed := false
+	for store := range persistenceSecretRefs {
+		path := []string{"spec", "configuration", "Persistence", store, "Configuration"}
+		config, found, err := unstructured.NestedMap(object.Object, path...)
+		if err != nil {
+			return false, fmt.Errorf("invalid %s configuration in '%s': %v", store, object.GetName(), err)
+		}
+		if !found {
+			continue
+		}
+		ref, found, err := unstructured.NestedStringMap(config, "passwordSecretRef")
+		if err != nil {
+			return false, fmt.Errorf("invalid %s password reference in '%s': %v", store, object.GetName(), err)
+		}
+		if !found {
+			continue
+		}
+		secret := &corev1.Secret{}
+		err = v1beta1.V1Client.Get(ctx, types.NamespacedName{Namespace: NS_DC_SYSTEM, Name: ref["name"]}, secret)
+		if apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("secret '%s' referenced by %s configuration in '%s' not found "+
+				"(run 'dcctl install infra' first)", ref["name"], store, object.GetName())
+		} else if err != nil {
+			return false, fmt.Errorf("unable to get secret '%s': %v", ref["name"], err)
+		}
+		password, ok := secret.Data[ref["key"]]
+		if !ok {
+			return false, fmt.Errorf("secret '%s' referenced by %s configuration in '%s' has no key '%s'",
+				ref["name"], store, object.GetName(), ref["key"])
+		}
+		delete(config, "passwordSecretRef")
+		config["password"] = string(password)
+		err = unstructured.SetNestedMap(object.Object, config, path...)
+		if err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// Hide passwords in the persistence configuration of an instance configuration so that they are
+// not shown in CLI output. Objects installed with inlined credentials still contain them.
+func redactInfraSecrets(object *unstructured.Unstructured) {
+	if object.GetKind() != "InstanceConfiguration" {
+		return
+	}
+	for store := range persistenceSecretRefs {
+		path := []string{"spec", "configuration", "Persistence", store, "Configuration", "password"}
+		if _, found, _ := unstructured.NestedFieldNoCopy(object.Object, path...); found {
+			_ = unstructured.SetNestedField(object.Object, REDACTED_PASSWORD, path...)
+		}
+	}
+}
+
+// Rewrite a resource manifest so that instance configurations reference secrets rather than
+// embedding passwords. Content is returned unchanged if there is nothing to replace.
+func secureResourceYaml(source string, content []byte) ([]byte, error) {
+	return rewriteResourceYaml(source, content, referenceInfraSecrets)
+}
+
+// Rewrite a resource manifest so that instance configurations embed the passwords from the secrets
+// created by install infra, including for resources generated by older versions that still contain
+// plaintext passwords.
+func inlineResourceYaml(ctx context.Context, source string, content []byte) ([]byte, error) {
+	return rewriteResourceYaml(source, content, func(object *unstructured.Unstructured) (bool, error) {
+		referenced, err := referenceInfraSecrets(object)
+		if err != nil {
+			return false, err
+		}
+		resolved, err := resolveInfraSecrets(ctx, object)
+		return referenced || resolved, err
+	})
+}
+
+// Rewrite objects in a resource manifest. Content is returned unchanged if no object was changed.
+func rewriteResourceYaml(source string, content []byte, rewrite func(*unstructured.Unstructured) (bool, error)) ([]byte, error) {
+	manifests, err := decodeManifest("resource", source, content)
+	if err != nil {
+		return nil, err
+	}
+	changed := false
+	for _, manifest := range manifests {
+		replaced, err := rewrite(manifest.Object)
+		if err != nil {
+			return nil, err
+		}
+		changed = changed || replaced
+	}
+	if !changed {
+		return content, nil
+	}
+	docs := make([]string, 0, len(manifests))
+	for _, manifest := range manifests {
+		b, err := yaml.Marshal(manifest.Object.Object)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, string(b))
+	}
+	return []byte(strings.Join(docs, "---\n")), nil
+}
@@ -0,0 +1,202 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"helm.sh/helm/v3/pkg/getter"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const instanceConfigurationYaml = `apiVersion: core.devicechain.io/v1beta1
+kind: InstanceConfiguration
+metadata:
+  name: dcic-default
+spec:
+  configuration:
+    Persistence:
+      Rdb:
+        Configuration:
+          password: devicechain
+          username: devicechain
+      Tsdb:
+        Configuration:
+          password: devicechain
+          username: postgres
+`
+
+func TestCheckCredentialValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  chartValues
+		wantErr string
+	}{
+		{name: "no values", values: chartValues{}},
+		{name: "other value", values: chartValues{"postgresql": {Values: []string{"primary.persistence.size=20Gi"}}}},
+		{name: "postgresql password", values: chartValues{"postgresql": {Values: []string{"auth.password=x"}}},
+			wantErr: "value 'auth.password' for chart 'postgresql'"},
+		{name: "keycloak database password", values: chartValues{"keycloak": {Values: []string{"externalDatabase.password=x"}}},
+			wantErr: "value 'externalDatabase.password' for chart 'keycloak'"},
+		{name: "password of chart without credentials", values: chartValues{"redis": {Values: []string{"auth.password=x"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCredentialValues(tt.values, getter.Providers{})
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCredentialOverrides(t *testing.T) {
+	creds := infraCredentials{INFRA_CREDENTIALS_SECRET: {POSTGRESQL_PASSWORD_KEY: "pg", KEYCLOAK_ADMIN_PASSWORD_KEY: "kc"}}
+	got := strings.Join(credentialOverrides("keycloak", creds), ",")
+	if want := "auth.adminPassword=kc,externalDatabase.password=pg"; got != want {
+		t.Errorf("overrides = %s, want %s", got, want)
+	}
+	if overrides := credentialOverrides("redis", creds); len(overrides) != 0 {
+		t.Errorf("unexpected overrides for redis: %v", overrides)
+	}
+}
+
+func TestAssureInfraSecrets(t *testing.T) {
+	claim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: NS_DC_SYSTEM,
+		Name: "data-dc-postgresql-0", Labels: map[string]string{"app.kubernetes.io/instance": "dc-postgresql"}}}
+	timescale := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: NS_DC_SYSTEM, Name: TIMESCALE_CREDENTIALS_SECRET},
+		Data: map[string][]byte{TIMESCALE_SUPERUSER_PASSWORD_KEY: []byte("existing")}}
+
+	tests := []struct {
+		name       string
+		objects    []client.Object
+		wantLegacy bool
+	}{
+		{name: "new install"},
+		{name: "install with existing database", objects: []client.Object{claim, timescale}, wantLegacy: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v1beta1.V1Client = fake.NewClientBuilder().WithObjects(tt.objects...).Build()
+			ctx := context.Background()
+			first, err := assureInfraSecrets(ctx, NewOperationReport("test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			password := first[INFRA_CREDENTIALS_SECRET][POSTGRESQL_PASSWORD_KEY]
+			if legacy := password == LEGACY_PASSWORD; legacy != tt.wantLegacy {
+				t.Errorf("postgresql password = %s, want legacy %v", password, tt.wantLegacy)
+			}
+			if !tt.wantLegacy && len(password) != GENERATED_PASSWORD_LENGTH {
+				t.Errorf("generated password has length %d", len(password))
+			}
+			if tt.wantLegacy && first[TIMESCALE_CREDENTIALS_SECRET][TIMESCALE_SUPERUSER_PASSWORD_KEY] != "existing" {
+				t.Error("existing timescale password was replaced")
+			}
+
+			// Credentials are generated once and reused afterwards.
+			second, err := assureInfraSecrets(ctx, NewOperationReport("test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, keys := range first {
+				for key, value := range keys {
+					if second[name][key] != value {
+						t.Errorf("credential %s/%s changed on second install", name, key)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestInlineResourceYaml(t *testing.T) {
+	v1beta1.V1Client = fake.NewClientBuilder().Build()
+	ctx := context.Background()
+	creds, err := assureInfraSecrets(ctx, NewOperationReport("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Generated resources reference secrets instead of containing passwords.
+	secured, err := secureResourceYaml("test", []byte(instanceConfigurationYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(secured), "password:") {
+		t.Errorf("secured resource still contains a password:\n%s", secured)
+	}
+
+	// Inlined resources take passwords from the secrets, whether generated before or after.
+	for _, content := range []string{instanceConfigurationYaml, string(secured)} {
+		inlined, err := inlineResourceYaml(ctx, "test", []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		manifests, err := decodeManifest("resource", "test", inlined)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]string{
+			"Rdb":  creds[INFRA_CREDENTIALS_SECRET][POSTGRESQL_PASSWORD_KEY],
+			"Tsdb": creds[TIMESCALE_CREDENTIALS_SECRET][TIMESCALE_SUPERUSER_PASSWORD_KEY],
+		}
+		for store, want := range expected {
+			got, _, _ := unstructured.NestedString(manifests[0].Object.Object, "spec", "configuration", "Persistence",
+				store, "Configuration", "password")
+			if got != want {
+				t.Errorf("%s password = %q, want %q", store, got, want)
+			}
+		}
+	}
+}
+
+func TestInlineResourceYamlRequiresSecrets(t *testing.T) {
+	v1beta1.V1Client = fake.NewClientBuilder().Build()
+	_, err := inlineResourceYaml(context.Background(), "test", []byte(instanceConfigurationYaml))
+	if err == nil || !strings.Contains(err.Error(), "install infra") {
+		t.Fatalf("error = %v, want missing secret error", err)
+	}
+	secret := &corev1.Secret{}
+	err = v1beta1.V1Client.Get(context.Background(), types.NamespacedName{Namespace: NS_DC_SYSTEM,
+		Name: INFRA_CREDENTIALS_SECRET}, secret)
+	if err == nil {
+		t.Error("secret was created while resolving references")
+	}
+}
+
+func TestNormalizedYamlRedactsPasswords(t *testing.T) {
+	manifests, err := decodeManifest("resource", "test", []byte(instanceConfigurationYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rendered, err := normalizedYaml(manifests[0].Object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(rendered, "password: devicechain") {
+		t.Errorf("rendered object contains a password:\n%s", rendered)
+	}
+	if !strings.Contains(rendered, "password: "+REDACTED_PASSWORD) {
+		t.Errorf("rendered object does not show redacted password:\n%s", rendered)
+	}
+	if got, _, _ := unstructured.NestedString(manifests[0].Object.Object, "spec", "configuration", "Persistence",
+		"Rdb", "Configuration", "password"); got != "devicechain" {
+		t.Errorf("original object was changed: password = %q", got)
+	}
+}
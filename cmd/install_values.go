@@ -0,0 +1,116 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+)
+
+// Helm values supplied on the command line, keyed by chart name.
+type chartValues map[string]*values.Options
+
+// Add flags used to supply Helm values for infrastructure charts.
+func addChartValuesFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayP("values", "f", []string{}, "values file for a chart as chart=path (e.g. postgresql=pg.yaml)")
+	cmd.Flags().StringArray("set", []string{}, "value for a chart as chart.key=value (e.g. postgresql.primary.persistence.size=20Gi)")
+}
+
+// Get names of charts embedded in the binary.
+func embeddedChartNames() ([]string, error) {
+	names := make([]string, 0)
+	err := fs.WalkDir(ChartFS, "install_infra/charts", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		cinfo, err := parseChartInfo(d)
+		if err != nil {
+			return err
+		}
+		names = append(names, cinfo.Chart)
+		return nil
+	})
+	return names, err
+}
+
+// Get values options for a chart, checking that the chart is one of the embedded charts.
+func (cv chartValues) options(chart string, known []string) (*values.Options, error) {
+	found := false
+	for _, name := range known {
+		if name == chart {
+			found = true
+		}
+	}
+	if !found {
+		sorted := append([]string{}, known...)
+		sort.Strings(sorted)
+		return nil, fmt.Errorf("unknown chart '%s' (expected one of %s)", chart, strings.Join(sorted, "|"))
+	}
+	if cv[chart] == nil {
+		cv[chart] = &values.Options{}
+	}
+	return cv[chart], nil
+}
+
+// Build values for each chart from the values and set flags.
+func chartValuesFromFlags(cmd *cobra.Command) (chartValues, error) {
+	known, err := embeddedChartNames()
+	if err != nil {
+		return nil, err
+	}
+	result := make(chartValues)
+
+	files, _ := cmd.Flags().GetStringArray("values")
+	for _, file := range files {
+		parts := strings.SplitN(file, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid values file '%s' (expected chart=path)", file)
+		}
+		if _, err := os.Stat(parts[1]); err != nil {
+			return nil, fmt.Errorf("unable to read values file for chart '%s': %v", parts[0], err)
+		}
+		opts, err := result.options(parts[0], known)
+		if err != nil {
+			return nil, err
+		}
+		opts.ValueFiles = append(opts.ValueFiles, parts[1])
+	}
+
+	sets, _ := cmd.Flags().GetStringArray("set")
+	for _, set := range sets {
+		parts := strings.SplitN(set, ".", 2)
+		if len(parts) != 2 || parts[0] == "" || !strings.Contains(parts[1], "=") {
+			return nil, fmt.Errorf("invalid value '%s' (expected chart.key=value)", set)
+		}
+		opts, err := result.options(parts[0], known)
+		if err != nil {
+			return nil, err
+		}
+		opts.Values = append(opts.Values, parts[1])
+	}
+	return result, nil
+}
+
+// Merge values supplied for a chart on top of the embedded overrides.
+func mergeChartValues(embedded map[string]interface{}, supplied *values.Options, p getter.Providers) (map[string]interface{}, error) {
+	if supplied == nil {
+		return embedded, nil
+	}
+	vals, err := supplied.MergeValues(p)
+	if err != nil {
+		return nil, err
+	}
+	return chartutil.CoalesceTables(vals, embedded), nil
+}
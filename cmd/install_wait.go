@@ -0,0 +1,375 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/release"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Name of the Kafka cluster created by install infra.
+	KAFKA_CLUSTER_NAME = "dc-kafka"
+)
+
+// Kind of Strimzi resource describing a Kafka cluster.
+var kafkaGVK = schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "Kafka"}
+
+// Readiness of a component checked after install.
+type componentReadiness struct {
+	ready  bool
+	detail string
+}
+
+// Component whose readiness is checked after install.
+type componentCheck struct {
+	kind      string
+	name      string
+	namespace string
+	selector  map[string]string
+	check     func(ctx context.Context) (componentReadiness, error)
+}
+
+// Display name of a component.
+func (c *componentCheck) String() string {
+	return fmt.Sprintf("%s/%s", c.kind, c.name)
+}
+
+// Add flags that control waiting for installed components to become ready.
+func addInstallWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", false, "wait until installed components are ready")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "maximum time to wait for components to become ready")
+}
+
+// Get the number of desired replicas, which defaults to one when not set.
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// Create a readiness check for a Deployment.
+func deploymentCheck(namespace string, name string, selector map[string]string) *componentCheck {
+	return &componentCheck{kind: "deployment", name: name, namespace: namespace, selector: selector,
+		check: func(ctx context.Context) (componentReadiness, error) {
+			deployment := &appsv1.Deployment{}
+			err := v1beta1.V1Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, deployment)
+			if apierrors.IsNotFound(err) {
+				return componentReadiness{detail: "not created yet"}, nil
+			} else if err != nil {
+				return componentReadiness{}, err
+			}
+			replicas := desiredReplicas(deployment.Spec.Replicas)
+			status := deployment.Status
+			ready := status.ObservedGeneration >= deployment.Generation && status.UpdatedReplicas >= replicas &&
+				status.AvailableReplicas >= replicas
+			return componentReadiness{ready: ready, detail: fmt.Sprintf("%d/%d available", status.AvailableReplicas, replicas)}, nil
+		}}
+}
+
+// Create a readiness check for a StatefulSet.
+func statefulSetCheck(namespace string, name string, selector map[string]string) *componentCheck {
+	return &componentCheck{kind: "statefulset", name: name, namespace: namespace, selector: selector,
+		check: func(ctx context.Context) (componentReadiness, error) {
+			set := &appsv1.StatefulSet{}
+			err := v1beta1.V1Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, set)
+			if apierrors.IsNotFound(err) {
+				return componentReadiness{detail: "not created yet"}, nil
+			} else if err != nil {
+				return componentReadiness{}, err
+			}
+			replicas := desiredReplicas(set.Spec.Replicas)
+			status := set.Status
+			ready := status.ObservedGeneration >= set.Generation && status.ReadyReplicas >= replicas
+			return componentReadiness{ready: ready, detail: fmt.Sprintf("%d/%d ready", status.ReadyReplicas, replicas)}, nil
+		}}
+}
+
+// Create a readiness check for the Strimzi Kafka cluster.
+func kafkaCheck(namespace string, name string) *componentCheck {
+	return &componentCheck{kind: "kafka", name: name, namespace: namespace,
+		selector: map[string]string{"strimzi.io/cluster": name},
+		check: func(ctx context.Context) (componentReadiness, error) {
+			kafka := &unstructured.Unstructured{}
+			kafka.SetGroupVersionKind(kafkaGVK)
+			err := v1beta1.V1Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, kafka)
+			if apierrors.IsNotFound(err) {
+				return componentReadiness{detail: "not created yet"}, nil
+			} else if err != nil {
+				return componentReadiness{}, err
+			}
+			for _, condition := range resourceConditions(kafka) {
+				if condition.Type != "Ready" && condition.Type != "NotReady" {
+					continue
+				}
+				if condition.Type == "Ready" && condition.Status == "True" {
+					return componentReadiness{ready: true, detail: "ready"}, nil
+				}
+				detail := strings.TrimSpace(fmt.Sprintf("%s %s", condition.Reason, condition.Message))
+				return componentReadiness{detail: detail}, nil
+			}
+			return componentReadiness{detail: "waiting for operator"}, nil
+		}}
+}
+
+// Create a readiness check for a Helm release.
+func helmReleaseCheck(settings *cli.EnvSettings, name string) *componentCheck {
+	return &componentCheck{kind: "helm release", name: name, namespace: NS_DC_SYSTEM,
+		check: func(ctx context.Context) (componentReadiness, error) {
+			actionConfig := new(action.Configuration)
+			if err := actionConfig.Init(settings.RESTClientGetter(), NS_DC_SYSTEM, os.Getenv("HELM_DRIVER"), helmDebug); err != nil {
+				return componentReadiness{}, err
+			}
+			rel, err := action.NewStatus(actionConfig).Run(name)
+			if err != nil {
+				return componentReadiness{}, err
+			}
+			switch rel.Info.Status {
+			case release.StatusDeployed:
+				return componentReadiness{ready: true, detail: string(rel.Info.Status)}, nil
+			case release.StatusFailed:
+				return componentReadiness{}, fmt.Errorf("helm release '%s' failed: %s", name, rel.Info.Description)
+			}
+			return componentReadiness{detail: string(rel.Info.Status)}, nil
+		}}
+}
+
+// Create checks for all Deployments and StatefulSets in a namespace.
+func workloadChecks(ctx context.Context, namespace string) ([]*componentCheck, error) {
+	checks := make([]*componentCheck, 0)
+	deployments := &appsv1.DeploymentList{}
+	err := v1beta1.V1Client.List(ctx, deployments, client.InNamespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+	for _, deployment := range deployments.Items {
+		checks = append(checks, deploymentCheck(namespace, deployment.Name, workloadSelector(deployment.Spec.Selector.MatchLabels)))
+	}
+	sets := &appsv1.StatefulSetList{}
+	err = v1beta1.V1Client.List(ctx, sets, client.InNamespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range sets.Items {
+		checks = append(checks, statefulSetCheck(namespace, set.Name, workloadSelector(set.Spec.Selector.MatchLabels)))
+	}
+	return checks, nil
+}
+
+// Copy labels used to select pods of a workload.
+func workloadSelector(labels map[string]string) map[string]string {
+	selector := make(map[string]string)
+	for key, value := range labels {
+		selector[key] = value
+	}
+	return selector
+}
+
+// Create checks for components installed by install infra.
+func infraComponentChecks(settings *cli.EnvSettings) func(ctx context.Context) ([]*componentCheck, error) {
+	return func(ctx context.Context) ([]*componentCheck, error) {
+		checks := make([]*componentCheck, 0)
+		err := fs.WalkDir(ChartFS, "install_infra/charts", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			cinfo, err := parseChartInfo(d)
+			if err != nil {
+				return err
+			}
+			checks = append(checks, helmReleaseCheck(settings, cinfo.Release))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, kafkaCheck(NS_DC_SYSTEM, KAFKA_CLUSTER_NAME))
+
+		// Workloads are listed on each pass since operators create them over time.
+		workloads, err := workloadChecks(ctx, NS_DC_SYSTEM)
+		if err != nil {
+			return nil, err
+		}
+		return append(checks, workloads...), nil
+	}
+}
+
+// Create checks for the operator components installed by install core.
+func coreComponentChecks(ctx context.Context) ([]*componentCheck, error) {
+	manifests, err := loadCoreManifests()
+	if err != nil {
+		return nil, err
+	}
+	checks := make([]*componentCheck, 0)
+	for _, manifest := range manifests {
+		if manifest.Group != "operator" {
+			continue
+		}
+		object := manifest.Object
+		namespace := object.GetNamespace()
+		selector, _, _ := unstructured.NestedStringMap(object.Object, "spec", "selector", "matchLabels")
+		switch object.GetKind() {
+		case "Deployment":
+			checks = append(checks, deploymentCheck(namespace, object.GetName(), selector))
+		case "StatefulSet":
+			checks = append(checks, statefulSetCheck(namespace, object.GetName(), selector))
+		}
+	}
+	return checks, nil
+}
+
+// Wait for components to become ready, printing progress as their status changes.
+func waitForComponents(components func(ctx context.Context) ([]*componentCheck, error), timeout time.Duration,
+	report *OperationReport) error {
+	fmt.Fprintln(output.Progress(), GreenUnderline("\nWait for Components"))
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	last := make(map[string]componentReadiness)
+	for {
+		checks, err := components(ctx)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		pending := make([]*componentCheck, 0)
+		for _, check := range checks {
+			readiness, err := check.check(ctx)
+			if err != nil && ctx.Err() == nil {
+				return err
+			}
+			if !readiness.ready {
+				pending = append(pending, check)
+			}
+			if previous, ok := last[check.String()]; ok && previous == readiness {
+				continue
+			}
+			last[check.String()] = readiness
+			if readiness.ready {
+				fmt.Fprintf(output.Progress(), "%s %s (%s)\n", color.HiGreenString("Ready:  "), check, readiness.detail)
+			} else {
+				fmt.Fprintf(output.Progress(), "%s %s (%s)\n", color.HiYellowString("Waiting:"), check, readiness.detail)
+			}
+		}
+		if err == nil && len(pending) == 0 {
+			for _, check := range checks {
+				report.Step(check.kind, check.name, "ready")
+			}
+			fmt.Fprintln(output.Progress(), color.HiGreenString("All %d components are ready.", len(checks)))
+			return nil
+		}
+		if !sleepContext(ctx, KUBE_POLL_INTERVAL) {
+			for _, check := range pending {
+				report.Step(check.kind, check.name, "not ready")
+			}
+			return readinessTimeoutError(pending, last, timeout)
+		}
+	}
+}
+
+// Build an error describing why components did not become ready.
+func readinessTimeoutError(pending []*componentCheck, last map[string]componentReadiness, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	lines := make([]string, 0)
+	namespaces := make(map[string]bool)
+	for _, check := range pending {
+		lines = append(lines, fmt.Sprintf("  %s: %s", check, last[check.String()].detail))
+		for _, problem := range diagnosePods(ctx, check.namespace, check.selector) {
+			lines = append(lines, "    "+problem)
+		}
+		namespaces[check.namespace] = true
+	}
+	for namespace := range namespaces {
+		for _, problem := range diagnoseClaims(ctx, namespace) {
+			lines = append(lines, "  "+problem)
+		}
+	}
+	return fmt.Errorf("timed out after %s waiting for %d components to become ready:\n%s", timeout, len(pending),
+		strings.Join(lines, "\n"))
+}
+
+// Describe problems with pods matching a selector such as failed image pulls or unschedulable pods.
+func diagnosePods(ctx context.Context, namespace string, selector map[string]string) []string {
+	problems := make([]string, 0)
+	if len(selector) == 0 {
+		return problems
+	}
+	pods := &corev1.PodList{}
+	err := v1beta1.V1Client.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels(selector))
+	if err != nil {
+		return append(problems, fmt.Sprintf("unable to list pods: %v", err))
+	}
+	for _, pod := range pods.Items {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+				problems = append(problems, fmt.Sprintf("pod %s is not scheduled: %s", pod.Name, condition.Message))
+			}
+		}
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			if waiting := status.State.Waiting; waiting != nil {
+				switch waiting.Reason {
+				case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+					problems = append(problems, fmt.Sprintf("pod %s can not pull image %s (%s): %s", pod.Name,
+						status.Image, waiting.Reason, waiting.Message))
+				case "CrashLoopBackOff":
+					problems = append(problems, fmt.Sprintf("pod %s container %s is crash-looping (%d restarts)",
+						pod.Name, status.Name, status.RestartCount))
+				case "CreateContainerConfigError":
+					problems = append(problems, fmt.Sprintf("pod %s container %s is misconfigured: %s", pod.Name,
+						status.Name, waiting.Message))
+				}
+			}
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// Describe persistent volume claims in a namespace that are still pending.
+func diagnoseClaims(ctx context.Context, namespace string) []string {
+	problems := make([]string, 0)
+	claims := &corev1.PersistentVolumeClaimList{}
+	err := v1beta1.V1Client.List(ctx, claims, client.InNamespace(namespace))
+	if err != nil {
+		return append(problems, fmt.Sprintf("unable to list persistent volume claims: %v", err))
+	}
+	for _, claim := range claims.Items {
+		if claim.Status.Phase != corev1.ClaimPending {
+			continue
+		}
+		storageClass := "<default>"
+		if claim.Spec.StorageClassName != nil {
+			storageClass = *claim.Spec.StorageClassName
+		}
+		problems = append(problems, fmt.Sprintf("persistent volume claim %s is pending (storage class %s)",
+			claim.Name, storageClass))
+	}
+	sort.Strings(problems)
+	return problems
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/output"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -43,12 +44,12 @@ func createInstance(args []string, name string, desc string) error {
 	}
 
 	req := buildInstanceRequestFromArgs(args, name, desc)
-	_, err := v1beta1.CreateInstance(req)
+	created, err := v1beta1.CreateInstance(req)
 	if err != nil {
 		return err
 	}
-	fmt.Printf(color.HiGreenString("Created DeviceChain instance '%s' successfully.\n"), args[0])
-	return nil
+	fmt.Fprintf(output.Progress(), color.HiGreenString("Created DeviceChain instance '%s' successfully.\n"), args[0])
+	return output.PrintResult(created)
 }
 
 // Build instance create request from command line args and flags
@@ -69,7 +70,7 @@ func buildInstanceRequestFromArgs(args []string, name string, desc string) v1bet
 }
 
 func init() {
-	createCmd.AddCommand(createInstanceCmd)
+	createCmd.AddCommand(requireKubernetes(createInstanceCmd))
 
 	createInstanceCmd.Flags().StringP("name", "n", "", "Specifies human-readable name for instance")
 	createInstanceCmd.Flags().StringP("desc", "d", "", "Specifies human-readable description for instance")
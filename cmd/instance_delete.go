@@ -0,0 +1,99 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Intialize command for deleting an instance
+var deleteInstanceCmd = NewDeleteInstanceCommand()
+
+// Create command that will delete instances
+func NewDeleteInstanceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "instance instance_id...",
+		Short: "Delete DeviceChain instances",
+		Long: `Deletes DeviceChain instances and waits for the operator to finalize them. Instances
+that still have tenants are only deleted if --cascade is passed, in which case the
+tenants are deleted first.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return deleteInstances(cmd, args)
+		}}
+	cmd.Flags().Bool("cascade", false, "delete tenants of the instance before deleting the instance")
+	addDeleteWaitFlags(cmd)
+	return cmd
+}
+
+// Delete DeviceChain instances
+func deleteInstances(cmd *cobra.Command, ids []string) error {
+	if len(ids) < 1 {
+		return errors.New("no instance id passed for DeviceChain instance to delete")
+	}
+	cascade, _ := cmd.Flags().GetBool("cascade")
+	wait, _ := cmd.Flags().GetBool("wait")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Check all instances before deleting anything.
+	instances := make([]*v1beta1.Instance, 0, len(ids))
+	tenants := make(map[string]*v1beta1.TenantList)
+	for _, id := range ids {
+		instance := &v1beta1.Instance{}
+		err := v1beta1.V1Beta1Client.Get(ctx, types.NamespacedName{Name: id}, instance)
+		if err != nil {
+			return fmt.Errorf("unable to get instance '%s': %v", id, err)
+		}
+		list := &v1beta1.TenantList{}
+		err = v1beta1.V1Beta1Client.List(ctx, list, client.InNamespace(id))
+		if err != nil {
+			return err
+		}
+		if len(list.Items) > 0 && !cascade {
+			names := make([]string, 0, len(list.Items))
+			for _, tenant := range list.Items {
+				names = append(names, tenant.Name)
+			}
+			return fmt.Errorf("instance '%s' still has tenants (%s); pass --cascade to delete them as well",
+				id, strings.Join(names, ", "))
+		}
+		instances = append(instances, instance)
+		tenants[id] = list
+	}
+
+	report := NewOperationReport("delete")
+	for _, instance := range instances {
+		list := tenants[instance.Name]
+		for i := range list.Items {
+			err := deleteResource(ctx, &list.Items[i], "tenant", wait, timeout, report)
+			if err != nil {
+				return err
+			}
+		}
+		err := deleteResource(ctx, instance, "instance", wait, timeout, report)
+		if err != nil {
+			return err
+		}
+	}
+	return report.Complete()
+}
+
+func init() {
+	deleteCmd.AddCommand(requireKubernetes(deleteInstanceCmd))
+}
@@ -0,0 +1,206 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Summary of a DeviceChain instance and the resources deployed for it.
+type InstanceSummary struct {
+	Id              string                 `json:"id"`
+	Name            string                 `json:"name"`
+	Description     string                 `json:"description"`
+	ConfigurationId string                 `json:"configurationId"`
+	Created         time.Time              `json:"created"`
+	Status          string                 `json:"status"`
+	Conditions      []ResourceCondition    `json:"conditions"`
+	Tenants         []string               `json:"tenants"`
+	Microservices   []*MicroserviceSummary `json:"microservices"`
+	age             string
+}
+
+// List of DeviceChain instances.
+type InstanceSummaryList struct {
+	Items []*InstanceSummary `json:"items"`
+}
+
+// Render instances as a table.
+func (list *InstanceSummaryList) Table() *output.Table {
+	table := output.NewTable(output.Column{Header: "ID"}, output.Column{Header: "NAME"},
+		output.Column{Header: "CONFIGURATION"}, output.Column{Header: "STATUS"}, output.Column{Header: "TENANTS"},
+		output.Column{Header: "MICROSERVICES"}, output.Column{Header: "AGE"},
+		output.Column{Header: "DESCRIPTION", Wide: true})
+	for _, instance := range list.Items {
+		table.AddRow(instance.Id, instance.Name, instance.ConfigurationId, instance.Status,
+			fmt.Sprintf("%d", len(instance.Tenants)), fmt.Sprintf("%d", len(instance.Microservices)),
+			instance.age, instance.Description)
+	}
+	return table
+}
+
+// Build summary of an instance including its tenants and microservices.
+func summarizeInstance(ctx context.Context, instance *v1beta1.Instance) (*InstanceSummary, error) {
+	tenants := &v1beta1.TenantList{}
+	err := v1beta1.V1Beta1Client.List(ctx, tenants, client.InNamespace(instance.Name))
+	if err != nil {
+		return nil, err
+	}
+	tenantIds := make([]string, 0, len(tenants.Items))
+	for _, tenant := range tenants.Items {
+		tenantIds = append(tenantIds, tenant.Name)
+	}
+	sort.Strings(tenantIds)
+	microservices := &v1beta1.MicroserviceList{}
+	err = v1beta1.V1Beta1Client.List(ctx, microservices, client.InNamespace(instance.Name))
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]*MicroserviceSummary, 0, len(microservices.Items))
+	for i := range microservices.Items {
+		summaries = append(summaries, summarizeMicroservice(&microservices.Items[i]))
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Id < summaries[j].Id
+	})
+
+	conditions := resourceConditions(instance)
+	return &InstanceSummary{
+		Id:              instance.Name,
+		Name:            instance.Spec.Name,
+		Description:     instance.Spec.Description,
+		ConfigurationId: instance.Spec.ConfigurationId,
+		Created:         instance.CreationTimestamp.Time,
+		Status:          conditionSummary(conditions),
+		Conditions:      conditions,
+		Tenants:         tenantIds,
+		Microservices:   summaries,
+		age:             resourceAge(instance.CreationTimestamp),
+	}, nil
+}
+
+// Get instances by id or list all instances.
+func getInstances(ctx context.Context, ids []string) (*InstanceSummaryList, error) {
+	instances := make([]*v1beta1.Instance, 0)
+	if len(ids) > 0 {
+		for _, id := range ids {
+			instance := &v1beta1.Instance{}
+			err := v1beta1.V1Beta1Client.Get(ctx, types.NamespacedName{Name: id}, instance)
+			if err != nil {
+				return nil, fmt.Errorf("unable to get instance '%s': %v", id, err)
+			}
+			instances = append(instances, instance)
+		}
+	} else {
+		list := &v1beta1.InstanceList{}
+		err := v1beta1.V1Beta1Client.List(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			instances = append(instances, &list.Items[i])
+		}
+		sort.SliceStable(instances, func(i, j int) bool {
+			return instances[i].Name < instances[j].Name
+		})
+	}
+
+	result := &InstanceSummaryList{Items: make([]*InstanceSummary, 0, len(instances))}
+	for _, instance := range instances {
+		summary, err := summarizeInstance(ctx, instance)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, summary)
+	}
+	return result, nil
+}
+
+// Create command that will display instances
+func newGetInstancesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "instances [instance_id...]",
+		Aliases:      []string{"instance"},
+		Short:        "Display DeviceChain instances",
+		Long:         `Displays DeviceChain instances with their configuration, status and deployed resources`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list, err := getInstances(context.Background(), args)
+			if err != nil {
+				return err
+			}
+			return output.Print(list)
+		}}
+}
+
+// Create command that will describe instances
+func newDescribeInstanceCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "instance instance_id...",
+		Aliases:      []string{"instances"},
+		Short:        "Show details of DeviceChain instances",
+		Long:         `Shows spec, status conditions, tenants and microservices of DeviceChain instances`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list, err := getInstances(context.Background(), args)
+			if err != nil {
+				return err
+			}
+			return output.PrintDetails(list, func(w io.Writer) error {
+				for _, instance := range list.Items {
+					describeInstance(w, instance)
+				}
+				return nil
+			})
+		}}
+}
+
+// Write details of an instance.
+func describeInstance(w io.Writer, instance *InstanceSummary) {
+	fmt.Fprintln(w, WhiteUnderline(fmt.Sprintf("Instance %s", instance.Id)))
+	fmt.Fprintf(w, "Name:           %s\n", instance.Name)
+	fmt.Fprintf(w, "Description:    %s\n", instance.Description)
+	fmt.Fprintf(w, "Configuration:  %s\n", instance.ConfigurationId)
+	fmt.Fprintf(w, "Created:        %s (%s ago)\n", instance.Created.Format(time.RFC3339), instance.age)
+	fmt.Fprintf(w, "Status:         %s\n", instance.Status)
+	describeConditions(w, instance.Conditions)
+	fmt.Fprintf(w, "Tenants:        %s\n", describeNames(instance.Tenants))
+	if len(instance.Microservices) == 0 {
+		fmt.Fprintln(w, "Microservices:  <none>")
+	} else {
+		fmt.Fprintln(w, "Microservices:")
+		for _, ms := range instance.Microservices {
+			fmt.Fprintf(w, "  %-24s %-20s %-24s %s\n", ms.Id, ms.FunctionalArea, ms.ConfigurationId, ms.Status)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// Format a list of names for describe output.
+func describeNames(names []string) string {
+	if len(names) == 0 {
+		return "<none>"
+	}
+	return strings.Join(names, ", ")
+}
+
+func init() {
+	getCmd.AddCommand(requireKubernetes(newGetInstancesCommand()))
+	describeCmd.AddCommand(newDescribeInstanceCommand())
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Intialize command for updating an instance
+var updateInstanceCmd = NewUpdateInstanceCommand()
+
+// Create command that will update an existing instance
+func NewUpdateInstanceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "instance instance_id",
+		Short:        "Update a DeviceChain instance",
+		Long:         `Updates the name, description or configuration of a DeviceChain instance`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateInstance(cmd, args)
+		}}
+	addResourceUpdateFlags(cmd, "instance", true)
+	return cmd
+}
+
+// Add flags for fields that may be changed on DeviceChain resources.
+func addResourceUpdateFlags(cmd *cobra.Command, resource string, configurable bool) {
+	cmd.Flags().StringP("name", "n", "", fmt.Sprintf("Specifies human-readable name for %s", resource))
+	cmd.Flags().StringP("desc", "d", "", fmt.Sprintf("Specifies human-readable description for %s", resource))
+	if configurable {
+		cmd.Flags().StringP("config-id", "c", "", fmt.Sprintf("Specifies id of configuration used by %s", resource))
+	}
+}
+
+// Apply a string flag to a field if the flag was passed.
+func applyResourceUpdateFlag(flags *pflag.FlagSet, name string, field *string) {
+	if flags.Changed(name) {
+		*field, _ = flags.GetString(name)
+	}
+}
+
+// Check that at least one field is changed by an update.
+func checkResourceUpdateFlags(flags *pflag.FlagSet) error {
+	for _, name := range []string{"name", "desc", "config-id"} {
+		if flags.Lookup(name) != nil && flags.Changed(name) {
+			return nil
+		}
+	}
+	return errors.New("nothing to update (pass --name, --desc or --config-id)")
+}
+
+// Update an existing DeviceChain instance
+func updateInstance(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("no instance id passed for DeviceChain instance to update")
+	}
+	flags := cmd.Flags()
+	if err := checkResourceUpdateFlags(flags); err != nil {
+		return err
+	}
+
+	instance := &v1beta1.Instance{}
+	instance.Name = args[0]
+	err := updateResource(context.Background(), instance, func() {
+		applyResourceUpdateFlag(flags, "name", &instance.Spec.Name)
+		applyResourceUpdateFlag(flags, "desc", &instance.Spec.Description)
+		applyResourceUpdateFlag(flags, "config-id", &instance.Spec.ConfigurationId)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to update instance '%s': %v", args[0], err)
+	}
+	fmt.Fprintf(output.Progress(), color.HiGreenString("Updated DeviceChain instance '%s' successfully.\n"), args[0])
+	return output.PrintResult(instance)
+}
+
+func init() {
+	updateCmd.AddCommand(requireKubernetes(updateInstanceCmd))
+}
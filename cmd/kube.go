@@ -0,0 +1,269 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	// Interval between checks while waiting on Kubernetes resources.
+	KUBE_POLL_INTERVAL = 2 * time.Second
+
+	// Annotation marking commands that use Kubernetes clients.
+	KUBERNETES_ANNOTATION = "dcctl.devicechain.io/kubernetes"
+)
+
+// Mark a command (and its subcommands) as using Kubernetes clients so that clients
+// are configured before it runs.
+func requireKubernetes(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[KUBERNETES_ANNOTATION] = "true"
+	return cmd
+}
+
+// Check whether a command or one of its parents uses Kubernetes clients.
+func usesKubernetes(cmd *cobra.Command) bool {
+	for current := cmd; current != nil; current = current.Parent() {
+		if current.Annotations[KUBERNETES_ANNOTATION] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// Configure Kubernetes clients if a kubeconfig or kube context was resolved for the command.
+// Otherwise the default client configuration from the environment is used.
+func configureKubernetes(cmd *cobra.Command) error {
+	kubeconfig := config.Kubeconfig.Resolve(cmd.Flags())
+	kubecontext := config.KubeContext.Resolve(cmd.Flags())
+	if kubeconfig == "" && kubecontext == "" {
+		return nil
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubecontext}
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	// Build a client that understands both core and DeviceChain types.
+	scheme := runtime.NewScheme()
+	err = clientgoscheme.AddToScheme(scheme)
+	if err != nil {
+		return err
+	}
+	err = v1beta1.AddToScheme(scheme)
+	if err != nil {
+		return err
+	}
+	mapper, err := apiutil.NewDynamicRESTMapper(restConfig, apiutil.WithLazyDiscovery)
+	if err != nil {
+		return err
+	}
+	cli, err := client.New(restConfig, client.Options{Scheme: scheme, Mapper: mapper})
+	if err != nil {
+		return err
+	}
+
+	v1beta1.ClientConfig = restConfig
+	v1beta1.V1Client = cli
+	v1beta1.V1Beta1Client = cli
+	return nil
+}
+
+// Status condition reported for a DeviceChain custom resource.
+type ResourceCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// Read status conditions from a custom resource. Conditions are read generically so that
+// any resource following the Kubernetes condition conventions is supported.
+func resourceConditions(obj runtime.Object) []ResourceCondition {
+	conditions := make([]ResourceCondition, 0)
+	fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return conditions
+	}
+	items, _, _ := unstructured.NestedSlice(fields, "status", "conditions")
+	for _, item := range items {
+		values, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditions = append(conditions, ResourceCondition{
+			Type:               entityValue(values, "type"),
+			Status:             entityValue(values, "status"),
+			Reason:             entityValue(values, "reason"),
+			Message:            entityValue(values, "message"),
+			LastTransitionTime: entityValue(values, "lastTransitionTime"),
+		})
+	}
+	return conditions
+}
+
+// Summarize conditions based on the Ready condition or the most recent condition.
+func conditionSummary(conditions []ResourceCondition) string {
+	for _, condition := range conditions {
+		if condition.Type == "Ready" {
+			if condition.Status == "True" {
+				return "Ready"
+			}
+			return "NotReady"
+		}
+	}
+	if len(conditions) == 0 {
+		return "Unknown"
+	}
+	last := conditions[len(conditions)-1]
+	return fmt.Sprintf("%s=%s", last.Type, last.Status)
+}
+
+// Get id of the instance that owns a namespaced resource. Owner references are used
+// when set, otherwise resources belong to the instance their namespace is named after.
+func owningInstance(obj metav1.Object) string {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == "Instance" {
+			return ref.Name
+		}
+	}
+	return obj.GetNamespace()
+}
+
+// Get human-readable age of a resource.
+func resourceAge(created metav1.Time) string {
+	if created.IsZero() {
+		return ""
+	}
+	return duration.HumanDuration(time.Since(created.Time))
+}
+
+// Write status conditions of a resource.
+func describeConditions(w io.Writer, conditions []ResourceCondition) {
+	if len(conditions) == 0 {
+		fmt.Fprintln(w, "Conditions:     <none>")
+		return
+	}
+	fmt.Fprintln(w, "Conditions:")
+	for _, condition := range conditions {
+		status := color.HiGreenString(condition.Status)
+		if condition.Status != "True" {
+			status = color.HiRedString(condition.Status)
+		}
+		fmt.Fprintf(w, "  %s=%s", condition.Type, status)
+		if condition.Reason != "" {
+			fmt.Fprintf(w, " (%s)", condition.Reason)
+		}
+		if condition.LastTransitionTime != "" {
+			fmt.Fprintf(w, " since %s", condition.LastTransitionTime)
+		}
+		fmt.Fprintln(w)
+		if condition.Message != "" {
+			fmt.Fprintf(w, "    %s\n", condition.Message)
+		}
+	}
+}
+
+// Delete a DeviceChain resource. If requested, waits until the operator has run
+// finalizers and the resource has been removed.
+func deleteResource(ctx context.Context, obj client.Object, kind string, wait bool, timeout time.Duration,
+	report *OperationReport) error {
+	name := obj.GetName()
+	if obj.GetNamespace() != "" {
+		name = client.ObjectKeyFromObject(obj).String()
+	}
+	err := v1beta1.V1Beta1Client.Delete(ctx, obj)
+	if apierrors.IsNotFound(err) {
+		report.Step(kind, name, "not found")
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to delete %s '%s': %v", kind, name, err)
+	}
+	if !wait {
+		report.Step(kind, name, "deleting")
+		fmt.Fprintf(output.Progress(), "Deletion of %s '%s' requested.\n", kind, name)
+		return nil
+	}
+
+	fmt.Fprintf(output.Progress(), "Waiting for %s '%s' to be finalized...\n", kind, name)
+	err = waitForResourceDeletion(ctx, obj, timeout)
+	if err != nil {
+		report.Step(kind, name, "failed")
+		return fmt.Errorf("%s '%s' was not deleted: %v", kind, name, err)
+	}
+	report.Step(kind, name, "deleted")
+	fmt.Fprintf(output.Progress(), color.HiGreenString("Deleted %s '%s'.\n"), kind, name)
+	return nil
+}
+
+// Wait until a resource no longer exists.
+func waitForResourceDeletion(ctx context.Context, obj client.Object, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	key := client.ObjectKeyFromObject(obj)
+	for {
+		err := v1beta1.V1Beta1Client.Get(ctx, key, obj)
+		if apierrors.IsNotFound(err) {
+			return nil
+		} else if err != nil && ctx.Err() == nil {
+			return err
+		}
+		if !sleepContext(ctx, KUBE_POLL_INTERVAL) {
+			if finalizers := obj.GetFinalizers(); len(finalizers) > 0 {
+				return fmt.Errorf("timed out waiting for finalizers (%s)", strings.Join(finalizers, ", "))
+			}
+			return errors.New("timed out waiting for removal")
+		}
+	}
+}
+
+// Add flags that control waiting for deleted resources to be finalized.
+func addDeleteWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", true, "wait for the operator to finalize deleted resources")
+	cmd.Flags().Duration("timeout", 5*time.Minute, "maximum time to wait for each resource to be finalized")
+}
+
+// Update a DeviceChain resource, reloading and reapplying changes if it was modified concurrently.
+func updateResource(ctx context.Context, obj client.Object, mutate func()) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := v1beta1.V1Beta1Client.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if err != nil {
+			return err
+		}
+		mutate()
+		return v1beta1.V1Beta1Client.Update(ctx, obj)
+	})
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/spf13/cobra"
+)
+
+func TestUsesKubernetes(t *testing.T) {
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{command: "install infra", want: true},
+		{command: "install core", want: true},
+		{command: "diff core", want: true},
+		{command: "get instances", want: true},
+		{command: "get tenants", want: true},
+		{command: "describe microservice", want: true},
+		{command: "create instance", want: true},
+		{command: "delete tenant", want: true},
+		{command: "update microservice", want: true},
+		{command: "event-sources list", want: true},
+		{command: "uninstall infra", want: true},
+		{command: "get devices", want: false},
+		{command: "create device", want: false},
+		{command: "delete asset", want: false},
+		{command: "events list", want: false},
+		{command: "users list", want: false},
+		{command: "login", want: false},
+		{command: "config view", want: false},
+		{command: "version", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			cmd, _, err := rootCmd.Find(strings.Fields(tt.command))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cmd.CommandPath() != "dcctl "+tt.command {
+				t.Fatalf("found command '%s'", cmd.CommandPath())
+			}
+			if got := usesKubernetes(cmd); got != tt.want {
+				t.Errorf("usesKubernetes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHelmSettings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("HELM_KUBECONTEXT", "")
+	t.Setenv(config.Kubeconfig.Env, "")
+	t.Setenv(config.KubeContext.Env, "")
+	tests := []struct {
+		name        string
+		args        []string
+		wantConfig  string
+		wantContext string
+	}{
+		{name: "flags", args: []string{"--kubeconfig", "/tmp/cluster-a", "--kube-context", "admin@a"},
+			wantConfig: "/tmp/cluster-a", wantContext: "admin@a"},
+		{name: "context only", args: []string{"--kube-context", "admin@b"}, wantContext: "admin@b"},
+		{name: "defaults", args: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			cmd.Flags().String(config.Kubeconfig.Name, "", "")
+			cmd.Flags().String(config.KubeContext.Name, "", "")
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			settings := helmSettings(cmd)
+			if settings.KubeConfig != tt.wantConfig || settings.KubeContext != tt.wantContext {
+				t.Errorf("kubeconfig = %q, kube context = %q", settings.KubeConfig, settings.KubeContext)
+			}
+		})
+	}
+}
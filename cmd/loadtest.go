@@ -0,0 +1,362 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/devicechain-io/dcctl/simulate"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// Token of device type assigned to devices created for load tests.
+	LOADTEST_DEVICE_TYPE = "loadtest"
+
+	// Interval between queries while waiting for published events to be persisted.
+	LOADTEST_POLL_INTERVAL = 2 * time.Second
+
+	// Window before the cursor of each device that is checked again for events persisted late.
+	LOADTEST_LOOKBACK = 30 * time.Second
+)
+
+// Intialize command for load testing
+var loadtestCmd = NewLoadTestCommand()
+
+// Create command that will load test event ingestion
+func NewLoadTestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "loadtest",
+		Short: "Load test event ingestion",
+		Long: `Ramps up a number of virtual devices publishing simulated events over MQTT at an
+aggregate rate for a duration. Once publishing stops, event management is queried until
+all published events have been persisted or the settle time passes. A JSON report
+includes ingest-to-persist latency percentiles, dropped messages and achieved throughput.`,
+		Example: `  dcctl loadtest --devices 100 --rate 500 --ramp 30s --duration 5m --create-devices
+  dcctl loadtest --devices 10 --rate 50 --duration 1m --report-file report.json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return loadTest(cmd)
+		}}
+	addTargetFlags(cmd.Flags())
+	addSimulationFlags(cmd)
+	cmd.Flags().Int("devices", 10, "number of virtual devices")
+	cmd.Flags().String("device-prefix", "loadtest-", "prefix of virtual device tokens")
+	cmd.Flags().Float64("rate", 100, "aggregate events per second across all devices")
+	cmd.Flags().Duration("ramp", 0, "time over which devices are started")
+	cmd.Flags().Duration("duration", time.Minute, "time spent publishing (including ramp)")
+	cmd.Flags().Duration("settle", 30*time.Second, "maximum time to wait for published events to be persisted")
+	cmd.Flags().Bool("create-devices", false, "create the device type and devices before publishing")
+	cmd.Flags().String("report-file", "", "also write the JSON report to the given file")
+	return cmd
+}
+
+// Latency percentiles in milliseconds.
+type LatencyReport struct {
+	Samples int     `json:"samples"`
+	Mean    float64 `json:"meanMs"`
+	P50     float64 `json:"p50Ms"`
+	P90     float64 `json:"p90Ms"`
+	P95     float64 `json:"p95Ms"`
+	P99     float64 `json:"p99Ms"`
+	Max     float64 `json:"maxMs"`
+}
+
+// Structured summary of a load test.
+type LoadTestReport struct {
+	Devices           int            `json:"devices"`
+	TargetRate        float64        `json:"targetRate"`
+	RampSeconds       float64        `json:"rampSeconds"`
+	Started           time.Time      `json:"started"`
+	Finished          time.Time      `json:"finished"`
+	Interrupted       bool           `json:"interrupted"`
+	Sent              int            `json:"sent"`
+	PublishFailed     int            `json:"publishFailed"`
+	Persisted         int            `json:"persisted"`
+	Dropped           int            `json:"dropped"`
+	DropRate          float64        `json:"dropRate"`
+	PublishThroughput float64        `json:"publishThroughput"`
+	PersistThroughput float64        `json:"persistThroughput"`
+	Latency           LatencyReport  `json:"latency"`
+	ByType            map[string]int `json:"byType"`
+}
+
+// Render load test summary as a table.
+func (r *LoadTestReport) Table() *output.Table {
+	table := output.NewTable(output.Column{Header: "METRIC"}, output.Column{Header: "VALUE"})
+	table.AddRow("devices", fmt.Sprintf("%d", r.Devices))
+	table.AddRow("sent", fmt.Sprintf("%d", r.Sent))
+	table.AddRow("publish failed", fmt.Sprintf("%d", r.PublishFailed))
+	table.AddRow("persisted", fmt.Sprintf("%d", r.Persisted))
+	table.AddRow("dropped", fmt.Sprintf("%d (%.2f%%)", r.Dropped, r.DropRate*100))
+	table.AddRow("publish throughput", fmt.Sprintf("%.1f events/sec", r.PublishThroughput))
+	table.AddRow("persist throughput", fmt.Sprintf("%.1f events/sec", r.PersistThroughput))
+	table.AddRow("latency p50", fmt.Sprintf("%.1f ms", r.Latency.P50))
+	table.AddRow("latency p90", fmt.Sprintf("%.1f ms", r.Latency.P90))
+	table.AddRow("latency p95", fmt.Sprintf("%.1f ms", r.Latency.P95))
+	table.AddRow("latency p99", fmt.Sprintf("%.1f ms", r.Latency.P99))
+	table.AddRow("latency max", fmt.Sprintf("%.1f ms", r.Latency.Max))
+	return table
+}
+
+// Get the value at a percentile of sorted samples using the nearest-rank method.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// Summarize latency samples in milliseconds.
+func latencyReport(samples []float64) LatencyReport {
+	report := LatencyReport{Samples: len(samples)}
+	if len(samples) == 0 {
+		return report
+	}
+	sort.Float64s(samples)
+	sum := 0.0
+	for _, sample := range samples {
+		sum += sample
+	}
+	report.Mean = sum / float64(len(samples))
+	report.P50 = percentile(samples, 50)
+	report.P90 = percentile(samples, 90)
+	report.P95 = percentile(samples, 95)
+	report.P99 = percentile(samples, 99)
+	report.Max = samples[len(samples)-1]
+	return report
+}
+
+// Tokens of virtual devices used for a load test.
+func loadTestDevices(prefix string, count int) []string {
+	devices := make([]string, 0, count)
+	width := len(fmt.Sprintf("%d", count))
+	for i := 1; i <= count; i++ {
+		devices = append(devices, fmt.Sprintf("%s%0*d", prefix, width, i))
+	}
+	return devices
+}
+
+// Create the load test device type and devices if they do not already exist.
+func createLoadTestDevices(ctx context.Context, cmd *cobra.Command, devices []string) error {
+	dm, err := gql.NewDeviceManagementGraphQLClient(cmd)
+	if err != nil {
+		return err
+	}
+	name := "Load Test Device"
+	description := "Virtual device used by dcctl loadtest"
+	_, _, err = dm.AssureDeviceType(ctx, LOADTEST_DEVICE_TYPE, &name, &description, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		deviceName := fmt.Sprintf("Load Test Device %s", device)
+		_, _, err = dm.AssureDevice(ctx, device, LOADTEST_DEVICE_TYPE, &deviceName, &description, nil)
+		if err != nil {
+			return err
+		}
+	}
+	created, found, _ := dm.Tracker.Counts()
+	fmt.Fprintf(output.Progress(), "Devices ready (%s created, %s found).\n",
+		color.HiGreenString("%d", created), color.HiGreenString("%d", found))
+	return nil
+}
+
+// Query events persisted for load test devices until all sent events are found or the settle time passes.
+// Each device is queried separately with a cursor so that only recently persisted events are downloaded.
+func collectPersistedEvents(ctx context.Context, cmd *cobra.Command, devices []string, start time.Time,
+	end time.Time, expected int, settle time.Duration) ([]map[string]interface{}, error) {
+	em, err := gql.NewEventManagementGraphQLClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+	cursors := make(map[string]*eventCursor)
+	for _, device := range devices {
+		cursors[device] = &eventCursor{from: start, position: start, lookback: LOADTEST_LOOKBACK,
+			seen: make(map[string]time.Time)}
+	}
+	deadline := time.Now().Add(settle)
+	persisted := make([]map[string]interface{}, 0)
+	for {
+		for _, device := range devices {
+			device := device
+			cursor := cursors[device]
+			from := cursor.start()
+			criteria := gql.EventSearchCriteria{PageSize: 500, DeviceToken: &device, StartTime: &from, EndTime: &end}
+			list, err := listAllEvents(ctx, &em, criteria)
+			if err != nil {
+				return persisted, err
+			}
+			persisted = append(persisted, cursor.advance(list.Items)...)
+		}
+		fmt.Fprintf(output.Progress(), "Persisted %d of %d events.\n", len(persisted), expected)
+		if len(persisted) >= expected || !time.Now().Add(LOADTEST_POLL_INTERVAL).Before(deadline) {
+			return persisted, nil
+		}
+		if !sleepContext(ctx, LOADTEST_POLL_INTERVAL) {
+			return persisted, ctx.Err()
+		}
+	}
+}
+
+// Build a load test report from publishing stats and persisted events.
+func newLoadTestReport(opts simulate.Options, stats *simulate.Stats, persisted []map[string]interface{}) *LoadTestReport {
+	report := &LoadTestReport{
+		Devices:       len(opts.Devices),
+		TargetRate:    opts.Rate * float64(len(opts.Devices)),
+		RampSeconds:   opts.Ramp.Seconds(),
+		Started:       stats.Started,
+		Finished:      stats.Finished,
+		Sent:          stats.Sent,
+		PublishFailed: stats.Failed,
+		Persisted:     len(persisted),
+		ByType:        stats.ByType,
+	}
+	if report.Sent > report.Persisted {
+		report.Dropped = report.Sent - report.Persisted
+	}
+	if report.Sent > 0 {
+		report.DropRate = float64(report.Dropped) / float64(report.Sent)
+	}
+	if elapsed := stats.Finished.Sub(stats.Started).Seconds(); elapsed > 0 {
+		report.PublishThroughput = float64(stats.Sent) / elapsed
+	}
+
+	samples := make([]float64, 0, len(persisted))
+	var first, last time.Time
+	for _, fields := range persisted {
+		occurred, oerr := time.Parse(time.RFC3339Nano, entityValue(fields, "occurredTime"))
+		processed, perr := time.Parse(time.RFC3339Nano, entityValue(fields, "processedTime"))
+		if oerr != nil || perr != nil {
+			continue
+		}
+		samples = append(samples, float64(processed.Sub(occurred))/float64(time.Millisecond))
+		if first.IsZero() || processed.Before(first) {
+			first = processed
+		}
+		if processed.After(last) {
+			last = processed
+		}
+	}
+	report.Latency = latencyReport(samples)
+	if elapsed := last.Sub(first).Seconds(); elapsed > 0 {
+		report.PersistThroughput = float64(len(samples)) / elapsed
+	}
+	return report
+}
+
+// Publish simulated events from virtual devices and measure how they are persisted
+func loadTest(cmd *cobra.Command) error {
+	count, _ := cmd.Flags().GetInt("devices")
+	if count < 1 {
+		return errors.New("devices must be greater than zero")
+	}
+	prefix, _ := cmd.Flags().GetString("device-prefix")
+	ramp, _ := cmd.Flags().GetDuration("ramp")
+	duration, _ := cmd.Flags().GetDuration("duration")
+	if duration <= 0 {
+		return errors.New("duration must be greater than zero")
+	}
+	if ramp > duration {
+		return errors.New("ramp may not be longer than duration")
+	}
+	settle, _ := cmd.Flags().GetDuration("settle")
+
+	// Stop publishing cleanly on Ctrl-C.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	devices := loadTestDevices(prefix, count)
+	opts, err := simulationOptionsFromFlags(cmd, devices)
+	if err != nil {
+		return err
+	}
+	// The rate flag is an aggregate across devices.
+	opts.Rate = opts.Rate / float64(count)
+	opts.Ramp = ramp
+	opts.Count = 0
+
+	if create, _ := cmd.Flags().GetBool("create-devices"); create {
+		fmt.Fprintf(output.Progress(), "Creating %d load test devices...\n", count)
+		if err := createLoadTestDevices(ctx, cmd, devices); err != nil {
+			return err
+		}
+	}
+
+	publisher, err := newMqttPublisherForCommand(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	sim, err := simulate.NewSimulator(opts, publisher)
+	if err != nil {
+		publisher.Close()
+		return err
+	}
+	fmt.Fprintf(output.Progress(), "Publishing %.1f events/sec from %d devices for %s (ramp %s, seed %d, press Ctrl-C to stop)...\n",
+		opts.Rate*float64(count), count, duration, ramp, opts.Seed)
+	stats := sim.Run(ctx)
+	publisher.Close()
+	interrupted := ctx.Err() != nil
+	stop()
+	fmt.Fprintf(output.Progress(), "Published %s events (%s failed) in %s.\n", color.HiGreenString("%d", stats.Sent),
+		color.HiRedString("%d", stats.Failed), stats.Finished.Sub(stats.Started).Round(time.Millisecond))
+
+	// Allow measurement to be interrupted separately from publishing.
+	qctx, qstop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer qstop()
+	if interrupted {
+		settle = 0
+	}
+	fmt.Fprintf(output.Progress(), "Waiting up to %s for events to be persisted...\n", settle)
+	persisted, err := collectPersistedEvents(qctx, cmd, devices, stats.Started.Add(-time.Second),
+		stats.Finished.Add(time.Second), stats.Sent, settle)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	report := newLoadTestReport(opts, stats, persisted)
+	report.Interrupted = interrupted || errors.Is(err, context.Canceled)
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if path, _ := cmd.Flags().GetString("report-file"); path != "" {
+		if err := os.WriteFile(path, append(b, '\n'), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(output.Progress(), "Report written to %s.\n", path)
+	}
+
+	// The report is printed as JSON unless another output format was requested.
+	if output.GetFormat() != output.FormatText {
+		return output.Print(report)
+	}
+	_, err = fmt.Println(string(b))
+	return err
+}
+
+func init() {
+	rootCmd.AddCommand(loadtestCmd)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/devicechain-io/dcctl/auth"
+	"github.com/devicechain-io/dcctl/config"
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+	"golang.org/x/term"
+)
+
+// Intialize command for logging in to Keycloak
+var loginCmd = NewLoginCommand()
+
+// Create command that will log in to Keycloak and cache tokens
+func NewLoginCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in to a DeviceChain instance",
+		Long: `Authenticates against the Keycloak realm for the targeted instance using either
+username and password or the device code flow. Tokens are cached for the active
+context and used for subsequent remote calls to the same server.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return login(cmd)
+		}}
+	addTargetFlags(cmd.Flags())
+	cmd.Flags().String("keycloak-url", "", "base url of Keycloak server (defaults to <scheme>://<server>:8080)")
+	cmd.Flags().String("realm", "", "Keycloak realm (defaults to instance id)")
+	cmd.Flags().String("client-id", "dcctl", "Keycloak client id")
+	cmd.Flags().StringP("username", "u", "", "username for password login")
+	cmd.Flags().Bool("password-stdin", false, "read password from stdin")
+	cmd.Flags().Bool("device", false, "log in using the device code flow in a browser")
+	return cmd
+}
+
+// Get realm information based on command flags and other settings.
+func getRealmForCommand(cmd *cobra.Command, opts *gql.TransportOptions) *auth.Realm {
+	flags := cmd.Flags()
+	baseUrl := config.KeycloakUrl.Resolve(flags)
+	if baseUrl == "" {
+		baseUrl = fmt.Sprintf("%s://%s:8080", opts.Scheme, config.Server.Resolve(flags))
+	}
+	realm := config.Realm.Resolve(flags)
+	if realm == "" {
+		realm = config.Instance.Resolve(flags)
+	}
+	return &auth.Realm{BaseUrl: baseUrl, Realm: realm, ClientId: config.ClientId.Resolve(flags)}
+}
+
+// Read password from stdin or prompt for it on the terminal.
+func readPassword(fromStdin bool) (string, error) {
+	if fromStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", errors.New("unable to read password from stdin")
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+	return promptPassword("Password: ")
+}
+
+// Prompt for a password on the terminal without echoing it.
+func promptPassword(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("no terminal available to prompt for password (use --password-stdin)")
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Log in using the device code flow.
+func deviceLogin(ctx context.Context, realm *auth.Realm) (*oauth2.Token, error) {
+	devauth, err := realm.StartDeviceLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	verify := devauth.VerificationUriComplete
+	if verify == "" {
+		verify = devauth.VerificationUri
+	}
+	fmt.Fprintf(os.Stderr, "Open %s in a browser and enter code %s\n", color.HiWhiteString(verify),
+		color.HiWhiteString(devauth.UserCode))
+	fmt.Fprintln(os.Stderr, "Waiting for login to complete...")
+	return realm.WaitForDeviceLogin(ctx, devauth)
+}
+
+// Log in and cache token for the active context
+func login(cmd *cobra.Command) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	// Use TLS and proxy settings for calls to Keycloak.
+	opts, err := gql.GetTransportOptionsForCommand(cmd)
+	if err != nil {
+		return err
+	}
+	transport, err := gql.NewBaseTransport(opts)
+	if err != nil {
+		return err
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: transport, Timeout: opts.Timeout})
+
+	realm := getRealmForCommand(cmd, opts)
+	device, _ := cmd.Flags().GetBool("device")
+	passwordStdin, _ := cmd.Flags().GetBool("password-stdin")
+
+	var token *oauth2.Token
+	username := config.Username.Resolve(cmd.Flags())
+	if device {
+		username = ""
+		token, err = deviceLogin(ctx, realm)
+	} else {
+		if username == "" {
+			return errors.New("no username passed (use --username or --device)")
+		}
+		// Only use the stored password for the username stored with it.
+		password := ""
+		if !passwordStdin && !cmd.Flags().Changed("username") {
+			password = config.Password.Resolve(cmd.Flags())
+		}
+		if password == "" {
+			password, err = readPassword(passwordStdin)
+			if err != nil {
+				return err
+			}
+		}
+		token, err = realm.PasswordLogin(ctx, username, password)
+	}
+	if err != nil {
+		return err
+	}
+
+	name := config.ActiveContextName()
+	err = auth.SaveToken(name, &auth.CachedToken{
+		TokenUrl: realm.TokenUrl(),
+		ClientId: realm.ClientId,
+		Username: username,
+		Scheme:   opts.Scheme,
+		Server:   config.Server.Resolve(cmd.Flags()),
+		Token:    token,
+	})
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = auth.DEFAULT_CACHE_NAME
+	}
+	fmt.Fprintf(output.Progress(), color.HiGreenString("Logged in to realm '%s' for context '%s'.\n"), realm.Realm, name)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/devicechain-io/dcctl/auth"
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Intialize command for removing cached tokens
+var logoutCmd = NewLogoutCommand()
+
+// Create command that will remove cached tokens for the active context
+func NewLogoutCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "logout",
+		Short:        "Log out of a DeviceChain instance",
+		Long:         `Removes tokens cached for the active context`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := config.ActiveContextName()
+			removed, err := auth.RemoveToken(name)
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				name = auth.DEFAULT_CACHE_NAME
+			}
+			if removed {
+				fmt.Fprintf(output.Progress(), color.HiGreenString("Logged out of context '%s'.\n"), name)
+			} else {
+				fmt.Fprintf(output.Progress(), "Not logged in to context '%s'.\n", name)
+			}
+			return nil
+		}}
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(logoutCmd)
+}
@@ -0,0 +1,230 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dck8s "github.com/devicechain-io/dc-k8s/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Field manager used when applying manifests server-side.
+	FIELD_MANAGER = "dcctl"
+
+	// Supported values for dry run flags.
+	DRY_RUN_NONE   = "none"
+	DRY_RUN_CLIENT = "client"
+	DRY_RUN_SERVER = "server"
+)
+
+// Single object from a manifest installed by install core.
+type coreManifest struct {
+	Group  string
+	Source string
+	Object *unstructured.Unstructured
+}
+
+// Description of the manifest object used in messages and reports.
+func (m *coreManifest) String() string {
+	name := m.Object.GetName()
+	if ns := m.Object.GetNamespace(); ns != "" {
+		name = ns + "/" + name
+	}
+	return fmt.Sprintf("%s %s", m.Object.GetKind(), name)
+}
+
+// Split a (possibly multi-document) manifest into objects.
+func decodeManifest(group string, source string, content []byte) ([]*coreManifest, error) {
+	manifests := make([]*coreManifest, 0)
+	decoder := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	for {
+		object := &unstructured.Unstructured{}
+		err := decoder.Decode(&object.Object)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to parse manifest '%s': %v", source, err)
+		}
+		if len(object.Object) == 0 {
+			continue
+		}
+		manifests = append(manifests, &coreManifest{Group: group, Source: source, Object: object})
+	}
+	return manifests, nil
+}
+
+// Load objects from all manifests installed by install core in the order they are applied.
+func loadCoreManifests() ([]*coreManifest, error) {
+	manifests := make([]*coreManifest, 0)
+	groups := []struct {
+		group  string
+		prefix string
+		files  embed.FS
+	}{
+		{group: "crd", prefix: "crd/bases", files: dck8s.CrdFiles()},
+		{group: "rbac", prefix: "rbac", files: dck8s.RbacFiles()},
+		{group: "operator", prefix: "manager", files: dck8s.ManagerFiles()},
+	}
+	for _, group := range groups {
+		resources, err := getEmbeddedContent(group.files, group.prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range resources {
+			decoded, err := decodeManifest(group.group, strings.TrimPrefix(resource.Name, group.prefix+"/"), resource.Content)
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, decoded...)
+		}
+	}
+
+	// Generated resources are optional.
+	err := filepath.Walk(GenResFolder, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		b, err = secureResourceYaml(path, b)
+		if err != nil {
+			return err
+		}
+		decoded, err := decodeManifest("resource", path, b)
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, decoded...)
+		return nil
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return manifests, nil
+}
+
+// Client used to compare manifest objects with live cluster objects.
+type manifestClient struct {
+	dynamic dynamic.Interface
+	mapper  meta.RESTMapper
+}
+
+// Create a manifest client from Kubernetes clients.
+func newManifestClient(dynamicClient dynamic.Interface, discoveryClient *discovery.DiscoveryClient) *manifestClient {
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+	return &manifestClient{dynamic: dynamicClient, mapper: mapper}
+}
+
+// Get the dynamic resource interface for an object.
+func (mc *manifestClient) resource(object *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := object.GroupVersionKind()
+	mapping, err := mc.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		namespace := object.GetNamespace()
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+		return mc.dynamic.Resource(mapping.Resource).Namespace(namespace), nil
+	}
+	return mc.dynamic.Resource(mapping.Resource), nil
+}
+
+// Get the live object for a manifest object. Returns nil if the object does not exist.
+func (mc *manifestClient) live(ctx context.Context, object *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	resource, err := mc.resource(object)
+	if err != nil {
+		return nil, err
+	}
+	live, err := resource.Get(ctx, object.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return live, err
+}
+
+// Apply an object server-side without persisting it. The server validates the object
+// and returns it as it would be stored, including defaulted fields.
+func (mc *manifestClient) dryRunApply(ctx context.Context, object *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	resource, err := mc.resource(object)
+	if err != nil {
+		return nil, err
+	}
+	data, err := object.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	force := true
+	return resource.Patch(ctx, object.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		DryRun:       []string{metav1.DryRunAll},
+		FieldManager: FIELD_MANAGER,
+		Force:        &force,
+	})
+}
+
+// Get the object that applying a manifest object would produce. A server-side dry run is
+// used if requested. Objects of kinds the cluster does not know about yet (usually custom
+// resources whose definitions are not installed) are returned as they appear in the manifest.
+func (mc *manifestClient) desired(ctx context.Context, object *unstructured.Unstructured, server bool) (*unstructured.Unstructured, error) {
+	if !server {
+		return object, nil
+	}
+	applied, err := mc.dryRunApply(ctx, object)
+	if meta.IsNoMatchError(err) {
+		return object, nil
+	}
+	return applied, err
+}
+
+// Render an object as YAML without fields that are managed by the server or passwords.
+func normalizedYaml(object *unstructured.Unstructured) (string, error) {
+	if object == nil {
+		return "", nil
+	}
+	clean := object.DeepCopy()
+	for _, field := range []string{"managedFields", "resourceVersion", "uid", "creationTimestamp", "generation", "selfLink"} {
+		unstructured.RemoveNestedField(clean.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(clean.Object, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
+	if annotations, found, _ := unstructured.NestedMap(clean.Object, "metadata", "annotations"); found && len(annotations) == 0 {
+		unstructured.RemoveNestedField(clean.Object, "metadata", "annotations")
+	}
+	unstructured.RemoveNestedField(clean.Object, "status")
+	redactInfraSecrets(clean)
+	b, err := yaml.Marshal(clean.Object)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
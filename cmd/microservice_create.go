@@ -12,6 +12,7 @@ import (
 	"time"
 
 	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/output"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -46,12 +47,12 @@ func createMicroservice(args []string, name string, desc string) error {
 	}
 
 	req := buildMicroserviceRequestFromArgs(args, name, desc)
-	_, err := v1beta1.CreateMicroservice(req)
+	created, err := v1beta1.CreateMicroservice(req)
 	if err != nil {
 		return err
 	}
-	fmt.Printf(color.HiGreenString("Created DeviceChain microservice '%s' successfully.\n"), args[1])
-	return nil
+	fmt.Fprintf(output.Progress(), color.HiGreenString("Created DeviceChain microservice '%s' successfully.\n"), args[1])
+	return output.PrintResult(created)
 }
 
 // Build microservice create request from command line args and flags
@@ -73,7 +74,7 @@ func buildMicroserviceRequestFromArgs(args []string, name string, desc string) v
 }
 
 func init() {
-	createCmd.AddCommand(createMicroserviceCmd)
+	createCmd.AddCommand(requireKubernetes(createMicroserviceCmd))
 
 	createMicroserviceCmd.Flags().StringP("name", "n", "", "Specifies human-readable name for microservice")
 	createMicroserviceCmd.Flags().StringP("desc", "d", "", "Specifies human-readable description for microservice")
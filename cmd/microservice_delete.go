@@ -0,0 +1,75 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Intialize command for deleting a microservice
+var deleteMicroserviceCmd = NewDeleteMicroserviceCommand()
+
+// Create command that will delete microservices
+func NewDeleteMicroserviceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "microservice instance_id microservice_id...",
+		Short:        "Delete DeviceChain microservices",
+		Long:         `Deletes microservices of a DeviceChain instance and waits for the operator to finalize them`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return deleteMicroservices(cmd, args)
+		}}
+	addDeleteWaitFlags(cmd)
+	return cmd
+}
+
+// Delete DeviceChain microservices
+func deleteMicroservices(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("no instance id passed for DeviceChain microservice to delete")
+	}
+	if len(args) < 2 {
+		return errors.New("no id passed for DeviceChain microservice to delete")
+	}
+	wait, _ := cmd.Flags().GetBool("wait")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Check all microservices before deleting anything.
+	instance := args[0]
+	microservices := make([]*v1beta1.Microservice, 0, len(args)-1)
+	for _, id := range args[1:] {
+		ms := &v1beta1.Microservice{}
+		err := v1beta1.V1Beta1Client.Get(ctx, types.NamespacedName{Namespace: instance, Name: id}, ms)
+		if err != nil {
+			return fmt.Errorf("unable to get microservice '%s' in instance '%s': %v", id, instance, err)
+		}
+		microservices = append(microservices, ms)
+	}
+
+	report := NewOperationReport("delete")
+	for _, ms := range microservices {
+		err := deleteResource(ctx, ms, "microservice", wait, timeout, report)
+		if err != nil {
+			return err
+		}
+	}
+	return report.Complete()
+}
+
+func init() {
+	deleteCmd.AddCommand(requireKubernetes(deleteMicroserviceCmd))
+}
@@ -0,0 +1,183 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Summary of a DeviceChain microservice.
+type MicroserviceSummary struct {
+	Instance        string              `json:"instance"`
+	Id              string              `json:"id"`
+	Name            string              `json:"name"`
+	Description     string              `json:"description"`
+	FunctionalArea  string              `json:"functionalArea"`
+	Image           string              `json:"image"`
+	ConfigurationId string              `json:"configurationId"`
+	Created         time.Time           `json:"created"`
+	Status          string              `json:"status"`
+	Conditions      []ResourceCondition `json:"conditions"`
+	age             string
+}
+
+// List of DeviceChain microservices.
+type MicroserviceSummaryList struct {
+	Items []*MicroserviceSummary `json:"items"`
+}
+
+// Render microservices as a table.
+func (list *MicroserviceSummaryList) Table() *output.Table {
+	table := output.NewTable(output.Column{Header: "INSTANCE"}, output.Column{Header: "ID"},
+		output.Column{Header: "FUNCTIONAL AREA"}, output.Column{Header: "CONFIGURATION"},
+		output.Column{Header: "STATUS"}, output.Column{Header: "AGE"}, output.Column{Header: "NAME", Wide: true},
+		output.Column{Header: "IMAGE", Wide: true})
+	for _, ms := range list.Items {
+		table.AddRow(ms.Instance, ms.Id, ms.FunctionalArea, ms.ConfigurationId, ms.Status, ms.age, ms.Name, ms.Image)
+	}
+	return table
+}
+
+// Build summary of a microservice.
+func summarizeMicroservice(ms *v1beta1.Microservice) *MicroserviceSummary {
+	conditions := resourceConditions(ms)
+	return &MicroserviceSummary{
+		Instance:        owningInstance(ms),
+		Id:              ms.Name,
+		Name:            ms.Spec.Name,
+		Description:     ms.Spec.Description,
+		FunctionalArea:  ms.Spec.FunctionalArea,
+		Image:           ms.Spec.Image,
+		ConfigurationId: ms.Spec.ConfigurationId,
+		Created:         ms.CreationTimestamp.Time,
+		Status:          conditionSummary(conditions),
+		Conditions:      conditions,
+		age:             resourceAge(ms.CreationTimestamp),
+	}
+}
+
+// Get microservices of an instance by id or list microservices of an instance. An empty
+// instance lists microservices across all instances.
+func getMicroservices(ctx context.Context, instance string, ids []string) (*MicroserviceSummaryList, error) {
+	microservices := make([]*v1beta1.Microservice, 0)
+	if len(ids) > 0 {
+		for _, id := range ids {
+			ms := &v1beta1.Microservice{}
+			err := v1beta1.V1Beta1Client.Get(ctx, types.NamespacedName{Namespace: instance, Name: id}, ms)
+			if err != nil {
+				return nil, fmt.Errorf("unable to get microservice '%s' in instance '%s': %v", id, instance, err)
+			}
+			microservices = append(microservices, ms)
+		}
+	} else {
+		list := &v1beta1.MicroserviceList{}
+		opts := make([]client.ListOption, 0)
+		if instance != "" {
+			opts = append(opts, client.InNamespace(instance))
+		}
+		err := v1beta1.V1Beta1Client.List(ctx, list, opts...)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			microservices = append(microservices, &list.Items[i])
+		}
+	}
+
+	result := &MicroserviceSummaryList{Items: make([]*MicroserviceSummary, 0, len(microservices))}
+	for _, ms := range microservices {
+		result.Items = append(result.Items, summarizeMicroservice(ms))
+	}
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		if result.Items[i].Instance != result.Items[j].Instance {
+			return result.Items[i].Instance < result.Items[j].Instance
+		}
+		return result.Items[i].Id < result.Items[j].Id
+	})
+	return result, nil
+}
+
+// Create command that will display microservices
+func newGetMicroservicesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "microservices [microservice_id...]",
+		Aliases:      []string{"microservice", "ms"},
+		Short:        "Display DeviceChain microservices",
+		Long:         `Displays microservices deployed for a DeviceChain instance or for all instances`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instance := resourceInstance(cmd)
+			if instance == "" && len(args) > 0 {
+				return errors.New("microservice ids may not be passed with --all-instances")
+			}
+			list, err := getMicroservices(context.Background(), instance, args)
+			if err != nil {
+				return err
+			}
+			return output.Print(list)
+		}}
+	addInstanceFlag(cmd.Flags())
+	cmd.Flags().BoolP("all-instances", "A", false, "display microservices of all instances")
+	return cmd
+}
+
+// Create command that will describe microservices
+func newDescribeMicroserviceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "microservice microservice_id...",
+		Aliases:      []string{"microservices", "ms"},
+		Short:        "Show details of DeviceChain microservices",
+		Long:         `Shows spec, status conditions and owning instance of DeviceChain microservices`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list, err := getMicroservices(context.Background(), config.Instance.Resolve(cmd.Flags()), args)
+			if err != nil {
+				return err
+			}
+			return output.PrintDetails(list, func(w io.Writer) error {
+				for _, ms := range list.Items {
+					describeMicroservice(w, ms)
+				}
+				return nil
+			})
+		}}
+	addInstanceFlag(cmd.Flags())
+	return cmd
+}
+
+// Write details of a microservice.
+func describeMicroservice(w io.Writer, ms *MicroserviceSummary) {
+	fmt.Fprintln(w, WhiteUnderline(fmt.Sprintf("Microservice %s", ms.Id)))
+	fmt.Fprintf(w, "Instance:         %s\n", ms.Instance)
+	fmt.Fprintf(w, "Name:             %s\n", ms.Name)
+	fmt.Fprintf(w, "Description:      %s\n", ms.Description)
+	fmt.Fprintf(w, "Functional Area:  %s\n", ms.FunctionalArea)
+	fmt.Fprintf(w, "Image:            %s\n", ms.Image)
+	fmt.Fprintf(w, "Configuration:    %s\n", ms.ConfigurationId)
+	fmt.Fprintf(w, "Created:          %s (%s ago)\n", ms.Created.Format(time.RFC3339), ms.age)
+	fmt.Fprintf(w, "Status:           %s\n", ms.Status)
+	describeConditions(w, ms.Conditions)
+	fmt.Fprintln(w)
+}
+
+func init() {
+	getCmd.AddCommand(requireKubernetes(newGetMicroservicesCommand()))
+	describeCmd.AddCommand(newDescribeMicroserviceCommand())
+}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Intialize command for updating a microservice
+var updateMicroserviceCmd = NewUpdateMicroserviceCommand()
+
+// Create command that will update an existing microservice
+func NewUpdateMicroserviceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "microservice instance_id microservice_id",
+		Short:        "Update a DeviceChain microservice",
+		Long:         `Updates the name, description or configuration of a DeviceChain microservice`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateMicroservice(cmd, args)
+		}}
+	addResourceUpdateFlags(cmd, "microservice", true)
+	return cmd
+}
+
+// Update an existing DeviceChain microservice
+func updateMicroservice(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("no instance id passed for DeviceChain microservice to update")
+	}
+	if len(args) < 2 {
+		return errors.New("no id passed for DeviceChain microservice to update")
+	}
+	flags := cmd.Flags()
+	if err := checkResourceUpdateFlags(flags); err != nil {
+		return err
+	}
+
+	ms := &v1beta1.Microservice{}
+	ms.Namespace = args[0]
+	ms.Name = args[1]
+	err := updateResource(context.Background(), ms, func() {
+		applyResourceUpdateFlag(flags, "name", &ms.Spec.Name)
+		applyResourceUpdateFlag(flags, "desc", &ms.Spec.Description)
+		applyResourceUpdateFlag(flags, "config-id", &ms.Spec.ConfigurationId)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to update microservice '%s' in instance '%s': %v", args[1], args[0], err)
+	}
+	fmt.Fprintf(output.Progress(), color.HiGreenString("Updated DeviceChain microservice '%s' successfully.\n"), args[1])
+	return output.PrintResult(ms)
+}
+
+func init() {
+	updateCmd.AddCommand(requireKubernetes(updateMicroserviceCmd))
+}
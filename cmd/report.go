@@ -0,0 +1,51 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"github.com/devicechain-io/dcctl/output"
+)
+
+// Single step executed as part of an operation.
+type OperationStep struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// Structured summary of a multi-step operation such as an install.
+type OperationReport struct {
+	Operation string          `json:"operation"`
+	Status    string          `json:"status"`
+	Steps     []OperationStep `json:"steps"`
+}
+
+// Create a report for the given operation.
+func NewOperationReport(operation string) *OperationReport {
+	return &OperationReport{Operation: operation, Status: "pending", Steps: make([]OperationStep, 0)}
+}
+
+// Add a step to the report.
+func (r *OperationReport) Step(kind string, name string, status string) {
+	r.Steps = append(r.Steps, OperationStep{Kind: kind, Name: name, Status: status})
+}
+
+// Mark the report as completed and print it if an output format was requested.
+func (r *OperationReport) Complete() error {
+	r.Status = "completed"
+	return output.PrintResult(r)
+}
+
+// Render report steps as a table.
+func (r *OperationReport) Table() *output.Table {
+	table := output.NewTable(output.Column{Header: "KIND"}, output.Column{Header: "NAME"},
+		output.Column{Header: "STATUS"})
+	for _, step := range r.Steps {
+		table.AddRow(step.Kind, step.Name, step.Status)
+	}
+	return table
+}
@@ -17,6 +17,7 @@ import (
 	gen "github.com/devicechain-io/dc-k8s/generators"
 	ms "github.com/devicechain-io/dc-microservice/config"
 	umgen "github.com/devicechain-io/dc-user-management/generator"
+	"github.com/devicechain-io/dcctl/output"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -29,18 +30,18 @@ var resgenCmd = &cobra.Command{
 	Short: "Generate configuration resources",
 	Long:  `Generates configuration resources directly from the microservice codebase`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("Generating resources from source code...")
+		fmt.Fprintln(output.Progress(), "Generating resources from source code...")
 		os.MkdirAll(GenResFolder, 0777)
 
 		// Generate instance resources
-		fmt.Println(GreenUnderline("\nInstance Resources"))
+		fmt.Fprintln(output.Progress(), GreenUnderline("\nInstance Resources"))
 		err := generateInstanceResources()
 		if err != nil {
 			return err
 		}
 
 		// Generate resources for each microservice
-		fmt.Println(GreenUnderline("\nMicroservice Resources"))
+		fmt.Fprintln(output.Progress(), GreenUnderline("\nMicroservice Resources"))
 		err = generateMicroserviceResources(
 			dmgen.ResourceProvider{},
 			emgen.ResourceProvider{},
@@ -63,6 +64,12 @@ func getInstanceResources() ([]gen.ConfigurationResource, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	// Reference infrastructure secrets rather than embedding passwords.
+	content, err = secureResourceYaml(name, content)
+	if err != nil {
+		return nil, err
+	}
 	dcidefault := gen.ConfigurationResource{
 		Name:    fmt.Sprintf("%s_%s", "core.devicechain.io", name),
 		Content: content,
@@ -84,9 +91,9 @@ func generateInstanceResources() error {
 		if err != nil {
 			return err
 		}
-		fmt.Printf(color.GreenString("Generated instance resource: %s\n"), color.HiWhiteString(path))
+		fmt.Fprintf(output.Progress(), color.GreenString("Generated instance resource: %s\n"), color.HiWhiteString(path))
 	}
-	fmt.Println()
+	fmt.Fprintln(output.Progress())
 	return nil
 }
 
@@ -103,10 +110,10 @@ func generateMicroserviceResources(providers ...gen.ConfigurationResourceProvide
 			if err != nil {
 				return err
 			}
-			fmt.Printf(color.GreenString("Generated microservice resource: %s\n"), color.HiWhiteString(path))
+			fmt.Fprintf(output.Progress(), color.GreenString("Generated microservice resource: %s\n"), color.HiWhiteString(path))
 		}
 	}
-	fmt.Println()
+	fmt.Fprintln(output.Progress())
 	return nil
 }
 
@@ -0,0 +1,199 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Create common command for managing roles
+var rolesCmd = &cobra.Command{
+	Use:     "roles",
+	Aliases: []string{"role"},
+	Short:   "Manage roles",
+	Long:    `Creates, displays and deletes roles and grants them to users`,
+}
+
+// Columns displayed for roles.
+var roleColumns = []entityColumn{
+	{Header: "NAME", Path: "name"},
+	{Header: "DESCRIPTION", Path: "description"},
+}
+
+// Create command that will create a role
+func newRolesCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "create name",
+		Short:        "Create a role",
+		Long:         `Creates a role with the given name`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			um, err := gql.NewUserManagementGraphQLClient(cmd)
+			if err != nil {
+				return err
+			}
+			req := gql.RoleCreateRequest{Name: args[0], Description: optionalStringFlag(cmd, "description")}
+			role, err := um.CreateRole(context.Background(), req)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(output.Progress(), color.HiGreenString("Created role '%s'.\n"), args[0])
+			return output.PrintResult(role)
+		}}
+	cmd.Flags().String("description", "", "description of role")
+	return cmd
+}
+
+// Create command that will list roles
+func newRolesListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List roles",
+		Long:         `Lists roles a page at a time`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			page, _ := cmd.Flags().GetInt("page")
+			size, _ := cmd.Flags().GetInt("page-size")
+			if page < 1 || size < 1 {
+				return errors.New("page and page-size must be greater than zero")
+			}
+			um, err := gql.NewUserManagementGraphQLClient(cmd)
+			if err != nil {
+				return err
+			}
+			roles, pagination, err := um.ListRoles(context.Background(), page, size)
+			if err != nil {
+				return err
+			}
+			list := &UmEntityList{Items: entityItems(roles), PageNumber: page, PageSize: size, columns: roleColumns}
+			if pagination != nil {
+				list.TotalRecords = pagination.GetTotalRecords()
+			}
+			return output.Print(list)
+		}}
+	addPagingFlags(cmd)
+	return cmd
+}
+
+// Create command that will get roles by name
+func newRolesGetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "get name...",
+		Short:        "Display roles",
+		Long:         `Displays roles by name`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			um, err := gql.NewUserManagementGraphQLClient(cmd)
+			if err != nil {
+				return err
+			}
+			roles, err := um.GetRolesByName(context.Background(), args)
+			if err != nil {
+				return err
+			}
+			list := &UmEntityList{Items: make([]interface{}, 0, len(args)), columns: roleColumns}
+			for _, name := range args {
+				role, ok := roles[name]
+				if !ok {
+					return fmt.Errorf("role '%s' not found", name)
+				}
+				list.Items = append(list.Items, role)
+			}
+			list.TotalRecords = len(list.Items)
+			return output.Print(list)
+		}}
+}
+
+// Create command that will delete roles
+func newRolesDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "delete name...",
+		Short:        "Delete roles",
+		Long:         `Deletes roles by name`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			um, err := gql.NewUserManagementGraphQLClient(cmd)
+			if err != nil {
+				return err
+			}
+			report := NewOperationReport("delete")
+			for _, name := range args {
+				if _, err := um.DeleteRole(context.Background(), name); err != nil {
+					return fmt.Errorf("unable to delete role '%s': %v", name, err)
+				}
+				report.Step("role", name, "deleted")
+				fmt.Fprintf(output.Progress(), color.HiGreenString("Deleted role '%s'.\n"), name)
+			}
+			return report.Complete()
+		}}
+}
+
+// Create command that will grant roles to a user
+func newRolesGrantCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "grant username role...",
+		Short:        "Grant roles to a user",
+		Long:         `Grants one or more roles to an existing user`,
+		Args:         cobra.MinimumNArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			um, err := gql.NewUserManagementGraphQLClient(cmd)
+			if err != nil {
+				return err
+			}
+			user, err := um.GrantRoles(context.Background(), args[0], args[1:])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(output.Progress(), color.HiGreenString("Granted %s to user '%s'.\n"), strings.Join(args[1:], ", "), args[0])
+			return output.PrintResult(user)
+		}}
+}
+
+// Create command that will revoke roles from a user
+func newRolesRevokeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "revoke username role...",
+		Short:        "Revoke roles from a user",
+		Long:         `Revokes one or more roles from an existing user`,
+		Args:         cobra.MinimumNArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			um, err := gql.NewUserManagementGraphQLClient(cmd)
+			if err != nil {
+				return err
+			}
+			user, err := um.RevokeRoles(context.Background(), args[0], args[1:])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(output.Progress(), color.HiGreenString("Revoked %s from user '%s'.\n"), strings.Join(args[1:], ", "), args[0])
+			return output.PrintResult(user)
+		}}
+}
+
+func init() {
+	addTargetFlags(rolesCmd.PersistentFlags())
+	rolesCmd.AddCommand(newRolesCreateCommand())
+	rolesCmd.AddCommand(newRolesListCommand())
+	rolesCmd.AddCommand(newRolesGetCommand())
+	rolesCmd.AddCommand(newRolesDeleteCommand())
+	rolesCmd.AddCommand(newRolesGrantCommand())
+	rolesCmd.AddCommand(newRolesRevokeCommand())
+	rootCmd.AddCommand(rolesCmd)
+}
@@ -7,8 +7,11 @@ Proprietary and confidential.
 package cmd
 
 import (
+	"fmt"
 	"os"
 
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/devicechain-io/dcctl/output"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -16,6 +19,18 @@ import (
 // Git commit info passed via makefile
 var gitCommit string
 
+// Path to configuration file passed via flag
+var cfgFile string
+
+// Name of context overriding the current context passed via flag
+var cfgContext string
+
+// Output format passed via flag
+var outputFormat string
+
+// Indicates colors should be disabled
+var noColor bool
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "dcctl",
@@ -28,6 +43,24 @@ var rootCmd = &cobra.Command{
 /_____/\___/|___/_/\___/\___/\____/_/ /_/\__,_/_/_/ /_/ 
                                                         
 Command line interface for interacting with DeviceChain components`),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := configureOutput()
+		if err != nil {
+			return err
+		}
+
+		// Surface configuration errors before any remote calls are made.
+		_, err = config.ActiveContext()
+		if err != nil {
+			return err
+		}
+
+		// Kubernetes clients are only configured for commands that use them.
+		if !usesKubernetes(cmd) {
+			return nil
+		}
+		return configureKubernetes(cmd)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -39,14 +72,25 @@ func Execute() {
 	}
 }
 
-func init() {
-	// Here you will define your flags and configuration settings.
-	// Cobra supports persistent flags, which, if defined here,
-	// will be global for your application.
+// Initialize configuration based on global flags.
+func initConfig() {
+	config.SetPath(cfgFile)
+	config.SetContextOverride(cfgContext)
+}
 
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.dcctl.yaml)")
+// Configure output format and colors based on global flags.
+func configureOutput() error {
+	output.ConfigureColor(noColor)
+	return output.SetFormat(outputFormat)
+}
+
+func init() {
+	cobra.OnInitialize(initConfig)
 
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
-	// rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.dcctl.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgContext, "context", "", "name of context to use instead of the current context")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", fmt.Sprintf("output format (%s)", output.FormatNames()))
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (automatic when stdout is not a terminal)")
+	rootCmd.PersistentFlags().String(config.Kubeconfig.Name, "", "path to kubeconfig file used for Kubernetes access")
+	rootCmd.PersistentFlags().String(config.KubeContext.Name, "", "name of kubeconfig context used for Kubernetes access")
 }
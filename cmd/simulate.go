@@ -0,0 +1,292 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devicechain-io/dcctl/config"
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/devicechain-io/dcctl/simulate"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// Topic that the default MQTT event source subscribes to.
+	DEFAULT_EVENTS_TOPIC = "devicechain/events"
+
+	// Port of the MQTT broker deployed by install infra.
+	DEFAULT_MQTT_PORT = 1883
+)
+
+// Intialize command for simulating devices
+var simulateCmd = NewSimulateCommand()
+
+// Create command that will publish simulated device events
+func NewSimulateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate [device_token...]",
+		Short: "Publish simulated device events",
+		Long: `Publishes synthetic measurements, random-walk locations and alerts over MQTT for the
+given devices or for every device in a device group. Events are spread evenly across
+devices at the configured rate until the duration or count is reached or the command
+is interrupted.`,
+		Example: `  dcctl simulate SDK7GV3WXZ3FBXZ WDVM4L7YPRM7HU2 --rate 2 --duration 5m
+  dcctl simulate --group smalldoz --types location --step 25
+  dcctl simulate SDK7GV3WXZ3FBXZ --count 5 --dry-run`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return simulateDevices(cmd, args)
+		}}
+	addTargetFlags(cmd.Flags())
+	addSimulationFlags(cmd)
+	cmd.Flags().String("group", "", "simulate every device in the device group with the given token")
+	cmd.Flags().Float64("rate", 1, "events per second for each device")
+	cmd.Flags().Duration("duration", 0, "stop after the given duration (runs until interrupted if not set)")
+	cmd.Flags().Int("count", 0, "stop after the given number of events per device")
+	cmd.Flags().Bool("dry-run", false, "print payloads to stdout instead of publishing them")
+	return cmd
+}
+
+// Add flags that control generated events and how they are published.
+func addSimulationFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("types", simulate.EventTypes, fmt.Sprintf("types of events to publish (%s)", strings.Join(simulate.EventTypes, "|")))
+	cmd.Flags().StringSlice("measurements", []string{"temperature", "humidity"}, "names of measurements included in measurement events")
+	cmd.Flags().Float64("alert-probability", 0.05, "probability that an event is an alert")
+	cmd.Flags().String("origin", "33.7490,-84.3880", "latitude,longitude where random-walk locations start")
+	cmd.Flags().Float64("step", 10, "average distance in meters moved between location events")
+	cmd.Flags().String("format", simulate.FORMAT_JSON, fmt.Sprintf("payload format (%s)", strings.Join(simulate.Formats, "|")))
+	cmd.Flags().String("template", "", "Go template file used to render payloads instead of a built-in format")
+	cmd.Flags().Int64("seed", 0, "seed for generated values (random if not set)")
+	cmd.Flags().String("broker", "", fmt.Sprintf("MQTT broker url (default tcp://<server>:%d)", DEFAULT_MQTT_PORT))
+	cmd.Flags().String("topic", DEFAULT_EVENTS_TOPIC, "topic events are published to ({device} is replaced by the device token)")
+	cmd.Flags().Int("qos", 0, "MQTT quality of service (0, 1 or 2)")
+	cmd.Flags().String("client-id", "", "MQTT client id (generated if not set)")
+	cmd.Flags().String("mqtt-username", "", "username for MQTT broker authentication")
+	cmd.Flags().String("mqtt-password", "", "password for MQTT broker authentication (or DCCTL_MQTT_PASSWORD)")
+}
+
+// Parse a latitude,longitude pair.
+func parseOrigin(value string) (float64, float64, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid origin '%s' (expected latitude,longitude)", value)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, fmt.Errorf("invalid origin latitude '%s'", parts[0])
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return 0, 0, fmt.Errorf("invalid origin longitude '%s'", parts[1])
+	}
+	return lat, lon, nil
+}
+
+// Build simulation options from command flags.
+func simulationOptionsFromFlags(cmd *cobra.Command, devices []string) (simulate.Options, error) {
+	flags := cmd.Flags()
+	opts := simulate.Options{Devices: devices}
+	opts.Rate, _ = flags.GetFloat64("rate")
+	opts.Duration, _ = flags.GetDuration("duration")
+	opts.Count, _ = flags.GetInt("count")
+	opts.Types, _ = flags.GetStringSlice("types")
+	opts.Measurements, _ = flags.GetStringSlice("measurements")
+	opts.AlertProbability, _ = flags.GetFloat64("alert-probability")
+	opts.StepMeters, _ = flags.GetFloat64("step")
+	opts.Topic, _ = flags.GetString("topic")
+	for i := range opts.Types {
+		opts.Types[i] = strings.ToLower(strings.TrimSpace(opts.Types[i]))
+	}
+
+	origin, _ := flags.GetString("origin")
+	var err error
+	opts.Latitude, opts.Longitude, err = parseOrigin(origin)
+	if err != nil {
+		return opts, err
+	}
+
+	opts.Seed, _ = flags.GetInt64("seed")
+	if !flags.Changed("seed") {
+		opts.Seed = time.Now().UnixNano()
+	}
+
+	template, _ := flags.GetString("template")
+	if template != "" {
+		opts.Format, err = simulate.TemplateFormat(template)
+	} else {
+		format, _ := flags.GetString("format")
+		opts.Format, err = simulate.FormatByName(format)
+	}
+	if err != nil {
+		return opts, err
+	}
+	return opts, opts.Validate()
+}
+
+// Create an MQTT publisher based on command flags and other settings.
+func newMqttPublisherForCommand(ctx context.Context, cmd *cobra.Command) (*simulate.MqttPublisher, error) {
+	flags := cmd.Flags()
+	broker, _ := flags.GetString("broker")
+	if broker == "" {
+		broker = fmt.Sprintf("tcp://%s:%d", config.Server.Resolve(flags), DEFAULT_MQTT_PORT)
+	}
+	parsed, err := url.Parse(broker)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid broker url '%s' (expected scheme://host:port)", broker)
+	}
+	qos, _ := flags.GetInt("qos")
+	if qos < 0 || qos > 2 {
+		return nil, errors.New("qos must be 0, 1 or 2")
+	}
+	clientId, _ := flags.GetString("client-id")
+	if clientId == "" {
+		clientId = fmt.Sprintf("dcctl-%d", os.Getpid())
+	}
+	username, _ := flags.GetString("mqtt-username")
+	password, _ := flags.GetString("mqtt-password")
+	if password == "" {
+		password = os.Getenv("DCCTL_MQTT_PASSWORD")
+	}
+
+	topts, err := gql.GetTransportOptionsForCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	opts := simulate.MqttOptions{
+		Broker:   broker,
+		ClientId: clientId,
+		Username: username,
+		Password: password,
+		Qos:      byte(qos),
+		Timeout:  topts.Timeout,
+		OnConnectionChange: func(connected bool, err error) {
+			if connected {
+				fmt.Fprintln(output.Progress(), color.HiGreenString("Reconnected to MQTT broker."))
+			} else {
+				fmt.Fprintf(output.Progress(), color.HiYellowString("Lost connection to MQTT broker: %v (reconnecting)\n"), err)
+			}
+		},
+	}
+	switch parsed.Scheme {
+	case "ssl", "tls", "mqtts", "wss":
+		opts.TlsConfig, err = gql.NewTlsConfig(topts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return simulate.NewMqttPublisher(ctx, opts)
+}
+
+// Get tokens of devices in a device group.
+func deviceGroupMembers(ctx context.Context, cmd *cobra.Command, group string) ([]string, error) {
+	dm, err := gql.NewDeviceManagementGraphQLClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+	kind, err := findDmKind("device-group-relationships")
+	if err != nil {
+		return nil, err
+	}
+	list, err := listAllDmEntities(ctx, &dm, kind, 100)
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, item := range list.Items {
+		fields, err := entityFields(item)
+		if err != nil {
+			return nil, err
+		}
+		device := entityValue(fields, "targetDevice.token")
+		if entityValue(fields, "source.token") != group || device == "" || seen[device] {
+			continue
+		}
+		seen[device] = true
+		devices = append(devices, device)
+	}
+	if len(devices) == 0 {
+		return nil, fmt.Errorf("no devices found in device group '%s'", group)
+	}
+	return devices, nil
+}
+
+// Resolve devices passed as arguments or as a device group.
+func simulatedDevices(ctx context.Context, cmd *cobra.Command, args []string) ([]string, error) {
+	group, _ := cmd.Flags().GetString("group")
+	if group != "" && len(args) > 0 {
+		return nil, errors.New("pass device tokens or --group, not both")
+	}
+	if group != "" {
+		return deviceGroupMembers(ctx, cmd, group)
+	}
+	if len(args) == 0 {
+		return nil, errors.New("no device tokens or --group passed")
+	}
+	return args, nil
+}
+
+// Publish simulated events for devices
+func simulateDevices(cmd *cobra.Command, args []string) error {
+	// Stop cleanly on Ctrl-C.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	devices, err := simulatedDevices(ctx, cmd, args)
+	if err != nil {
+		return err
+	}
+	opts, err := simulationOptionsFromFlags(cmd, devices)
+	if err != nil {
+		return err
+	}
+
+	var publisher simulate.Publisher
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		publisher = simulate.NewWriterPublisher(os.Stdout)
+	} else {
+		publisher, err = newMqttPublisherForCommand(ctx, cmd)
+		if err != nil {
+			return err
+		}
+	}
+	defer publisher.Close()
+
+	sim, err := simulate.NewSimulator(opts, publisher)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output.Progress(), "Simulating %d devices at %.2f events/sec each (seed %d, press Ctrl-C to stop)...\n",
+		len(devices), opts.Rate, opts.Seed)
+	stats := sim.Run(ctx)
+
+	elapsed := stats.Finished.Sub(stats.Started)
+	fmt.Fprintf(output.Progress(), "\nPublished %s events (%s failed) in %s.\n", color.HiGreenString("%d", stats.Sent),
+		color.HiRedString("%d", stats.Failed), elapsed.Round(time.Millisecond))
+	if err := output.PrintResult(stats); err != nil {
+		return err
+	}
+	if stats.Failed > 0 {
+		return fmt.Errorf("failed to publish %d events", stats.Failed)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(simulateCmd)
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/output"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -43,12 +44,12 @@ func createTenant(args []string, name string, desc string) error {
 	}
 
 	req := buildTenantRequestFromArgs(args, name, desc)
-	_, err := v1beta1.CreateTenant(req)
+	created, err := v1beta1.CreateTenant(req)
 	if err != nil {
 		return err
 	}
-	fmt.Printf(color.HiGreenString("Created DeviceChain tenant '%s' successfully.\n"), args[1])
-	return nil
+	fmt.Fprintf(output.Progress(), color.HiGreenString("Created DeviceChain tenant '%s' successfully.\n"), args[1])
+	return output.PrintResult(created)
 }
 
 // Build tenant create request from command line args and flags
@@ -69,7 +70,7 @@ func buildTenantRequestFromArgs(args []string, name string, desc string) v1beta1
 }
 
 func init() {
-	createCmd.AddCommand(createTenantCmd)
+	createCmd.AddCommand(requireKubernetes(createTenantCmd))
 
 	createTenantCmd.Flags().StringP("name", "n", "", "Specifies human-readable name for tenant")
 	createTenantCmd.Flags().StringP("desc", "d", "", "Specifies human-readable description for tenant")
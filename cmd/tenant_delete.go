@@ -0,0 +1,75 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Intialize command for deleting a tenant
+var deleteTenantCmd = NewDeleteTenantCommand()
+
+// Create command that will delete tenants
+func NewDeleteTenantCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "tenant instance_id tenant_id...",
+		Short:        "Delete DeviceChain tenants",
+		Long:         `Deletes tenants of a DeviceChain instance and waits for the operator to finalize them`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return deleteTenants(cmd, args)
+		}}
+	addDeleteWaitFlags(cmd)
+	return cmd
+}
+
+// Delete DeviceChain tenants
+func deleteTenants(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("no instance id passed for DeviceChain tenant to delete")
+	}
+	if len(args) < 2 {
+		return errors.New("no tenant id passed for DeviceChain tenant to delete")
+	}
+	wait, _ := cmd.Flags().GetBool("wait")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Check all tenants before deleting anything.
+	instance := args[0]
+	tenants := make([]*v1beta1.Tenant, 0, len(args)-1)
+	for _, id := range args[1:] {
+		tenant := &v1beta1.Tenant{}
+		err := v1beta1.V1Beta1Client.Get(ctx, types.NamespacedName{Namespace: instance, Name: id}, tenant)
+		if err != nil {
+			return fmt.Errorf("unable to get tenant '%s' in instance '%s': %v", id, instance, err)
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	report := NewOperationReport("delete")
+	for _, tenant := range tenants {
+		err := deleteResource(ctx, tenant, "tenant", wait, timeout, report)
+		if err != nil {
+			return err
+		}
+	}
+	return report.Complete()
+}
+
+func init() {
+	deleteCmd.AddCommand(requireKubernetes(deleteTenantCmd))
+}
@@ -0,0 +1,182 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Summary of a DeviceChain tenant.
+type TenantSummary struct {
+	Instance    string              `json:"instance"`
+	Id          string              `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Created     time.Time           `json:"created"`
+	Status      string              `json:"status"`
+	Conditions  []ResourceCondition `json:"conditions"`
+	age         string
+}
+
+// List of DeviceChain tenants.
+type TenantSummaryList struct {
+	Items []*TenantSummary `json:"items"`
+}
+
+// Render tenants as a table.
+func (list *TenantSummaryList) Table() *output.Table {
+	table := output.NewTable(output.Column{Header: "INSTANCE"}, output.Column{Header: "ID"},
+		output.Column{Header: "NAME"}, output.Column{Header: "STATUS"}, output.Column{Header: "AGE"},
+		output.Column{Header: "DESCRIPTION", Wide: true})
+	for _, tenant := range list.Items {
+		table.AddRow(tenant.Instance, tenant.Id, tenant.Name, tenant.Status, tenant.age, tenant.Description)
+	}
+	return table
+}
+
+// Build summary of a tenant.
+func summarizeTenant(tenant *v1beta1.Tenant) *TenantSummary {
+	conditions := resourceConditions(tenant)
+	return &TenantSummary{
+		Instance:    owningInstance(tenant),
+		Id:          tenant.Name,
+		Name:        tenant.Spec.Name,
+		Description: tenant.Spec.Description,
+		Created:     tenant.CreationTimestamp.Time,
+		Status:      conditionSummary(conditions),
+		Conditions:  conditions,
+		age:         resourceAge(tenant.CreationTimestamp),
+	}
+}
+
+// Get tenants of an instance by id or list tenants of an instance. An empty
+// instance lists tenants across all instances.
+func getTenants(ctx context.Context, instance string, ids []string) (*TenantSummaryList, error) {
+	tenants := make([]*v1beta1.Tenant, 0)
+	if len(ids) > 0 {
+		for _, id := range ids {
+			tenant := &v1beta1.Tenant{}
+			err := v1beta1.V1Beta1Client.Get(ctx, types.NamespacedName{Namespace: instance, Name: id}, tenant)
+			if err != nil {
+				return nil, fmt.Errorf("unable to get tenant '%s' in instance '%s': %v", id, instance, err)
+			}
+			tenants = append(tenants, tenant)
+		}
+	} else {
+		list := &v1beta1.TenantList{}
+		opts := make([]client.ListOption, 0)
+		if instance != "" {
+			opts = append(opts, client.InNamespace(instance))
+		}
+		err := v1beta1.V1Beta1Client.List(ctx, list, opts...)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			tenants = append(tenants, &list.Items[i])
+		}
+	}
+
+	result := &TenantSummaryList{Items: make([]*TenantSummary, 0, len(tenants))}
+	for _, tenant := range tenants {
+		result.Items = append(result.Items, summarizeTenant(tenant))
+	}
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		if result.Items[i].Instance != result.Items[j].Instance {
+			return result.Items[i].Instance < result.Items[j].Instance
+		}
+		return result.Items[i].Id < result.Items[j].Id
+	})
+	return result, nil
+}
+
+// Get instance whose resources are displayed. An empty instance is returned when all
+// instances were requested.
+func resourceInstance(cmd *cobra.Command) string {
+	if all, _ := cmd.Flags().GetBool("all-instances"); all {
+		return ""
+	}
+	return config.Instance.Resolve(cmd.Flags())
+}
+
+// Create command that will display tenants
+func newGetTenantsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "tenants [tenant_id...]",
+		Aliases:      []string{"tenant"},
+		Short:        "Display DeviceChain tenants",
+		Long:         `Displays tenants of a DeviceChain instance or of all instances`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instance := resourceInstance(cmd)
+			if instance == "" && len(args) > 0 {
+				return errors.New("tenant ids may not be passed with --all-instances")
+			}
+			list, err := getTenants(context.Background(), instance, args)
+			if err != nil {
+				return err
+			}
+			return output.Print(list)
+		}}
+	addInstanceFlag(cmd.Flags())
+	cmd.Flags().BoolP("all-instances", "A", false, "display tenants of all instances")
+	return cmd
+}
+
+// Create command that will describe tenants
+func newDescribeTenantCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "tenant tenant_id...",
+		Aliases:      []string{"tenants"},
+		Short:        "Show details of DeviceChain tenants",
+		Long:         `Shows spec, status conditions and owning instance of DeviceChain tenants`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list, err := getTenants(context.Background(), config.Instance.Resolve(cmd.Flags()), args)
+			if err != nil {
+				return err
+			}
+			return output.PrintDetails(list, func(w io.Writer) error {
+				for _, tenant := range list.Items {
+					describeTenant(w, tenant)
+				}
+				return nil
+			})
+		}}
+	addInstanceFlag(cmd.Flags())
+	return cmd
+}
+
+// Write details of a tenant.
+func describeTenant(w io.Writer, tenant *TenantSummary) {
+	fmt.Fprintln(w, WhiteUnderline(fmt.Sprintf("Tenant %s", tenant.Id)))
+	fmt.Fprintf(w, "Instance:       %s\n", tenant.Instance)
+	fmt.Fprintf(w, "Name:           %s\n", tenant.Name)
+	fmt.Fprintf(w, "Description:    %s\n", tenant.Description)
+	fmt.Fprintf(w, "Created:        %s (%s ago)\n", tenant.Created.Format(time.RFC3339), tenant.age)
+	fmt.Fprintf(w, "Status:         %s\n", tenant.Status)
+	describeConditions(w, tenant.Conditions)
+	fmt.Fprintln(w)
+}
+
+func init() {
+	getCmd.AddCommand(requireKubernetes(newGetTenantsCommand()))
+	describeCmd.AddCommand(newDescribeTenantCommand())
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v1beta1 "github.com/devicechain-io/dc-k8s/api/v1beta1"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Intialize command for updating a tenant
+var updateTenantCmd = NewUpdateTenantCommand()
+
+// Create command that will update an existing tenant
+func NewUpdateTenantCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "tenant instance_id tenant_id",
+		Short:        "Update a DeviceChain tenant",
+		Long:         `Updates the name or description of a DeviceChain tenant`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateTenant(cmd, args)
+		}}
+	addResourceUpdateFlags(cmd, "tenant", false)
+	return cmd
+}
+
+// Update an existing DeviceChain tenant
+func updateTenant(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("no instance id passed for DeviceChain tenant to update")
+	}
+	if len(args) < 2 {
+		return errors.New("no tenant id passed for DeviceChain tenant to update")
+	}
+	flags := cmd.Flags()
+	if err := checkResourceUpdateFlags(flags); err != nil {
+		return err
+	}
+
+	tenant := &v1beta1.Tenant{}
+	tenant.Namespace = args[0]
+	tenant.Name = args[1]
+	err := updateResource(context.Background(), tenant, func() {
+		applyResourceUpdateFlag(flags, "name", &tenant.Spec.Name)
+		applyResourceUpdateFlag(flags, "desc", &tenant.Spec.Description)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to update tenant '%s' in instance '%s': %v", args[1], args[0], err)
+	}
+	fmt.Fprintf(output.Progress(), color.HiGreenString("Updated DeviceChain tenant '%s' successfully.\n"), args[1])
+	return output.PrintResult(tenant)
+}
+
+func init() {
+	updateCmd.AddCommand(requireKubernetes(updateTenantCmd))
+}
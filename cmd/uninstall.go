@@ -18,5 +18,5 @@ var uninstallCmd = &cobra.Command{
 }
 
 func init() {
-	rootCmd.AddCommand(uninstallCmd)
+	rootCmd.AddCommand(requireKubernetes(uninstallCmd))
 }
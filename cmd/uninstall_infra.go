@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io/fs"
 
+	"github.com/devicechain-io/dcctl/output"
 	"github.com/fatih/color"
 
 	"github.com/spf13/cobra"
@@ -27,28 +28,28 @@ func NewUninstallInfraCommand() *cobra.Command {
 		Long:         `Uninstalls DeviceChain infrastructure dependencies`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return uninstallInfraComponents()
+			return uninstallInfraComponents(helmSettings(cmd))
 		},
 	}
 }
 
 // Uninstall infrastructure components.
-func uninstallInfraComponents() error {
-	fmt.Println("Preparing to uninstall DeviceChain infrastructure components...")
+func uninstallInfraComponents(settings *cli.EnvSettings) error {
+	fmt.Fprintln(output.Progress(), "Preparing to uninstall DeviceChain infrastructure components...")
+	report := NewOperationReport("uninstall infra")
 
-	settings := cli.New()
-	err := uninstallHelmReleases(settings)
+	err := uninstallHelmReleases(settings, report)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(color.HiGreenString("\nUninstall completed successfully."))
-	return nil
+	fmt.Fprintln(output.Progress(), color.HiGreenString("\nUninstall completed successfully."))
+	return report.Complete()
 }
 
 // Uninstall Helm releases for each chart embedded in the binary.
-func uninstallHelmReleases(settings *cli.EnvSettings) error {
-	fmt.Println(GreenUnderline("\nUnnstall Helm Charts"))
+func uninstallHelmReleases(settings *cli.EnvSettings, report *OperationReport) error {
+	fmt.Fprintln(output.Progress(), GreenUnderline("\nUnnstall Helm Charts"))
 	return fs.WalkDir(ChartFS, "install_infra/charts", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -58,7 +59,7 @@ func uninstallHelmReleases(settings *cli.EnvSettings) error {
 			if err != nil {
 				return err
 			}
-			fmt.Printf("Uninstalling Helm Chart: Repository: %s Chart: %s Version: %s Release: %s\n",
+			fmt.Fprintf(output.Progress(), "Uninstalling Helm Chart: Repository: %s Chart: %s Version: %s Release: %s\n",
 				color.GreenString(cinfo.Repository),
 				color.GreenString(cinfo.Chart),
 				color.GreenString(cinfo.Version),
@@ -69,6 +70,7 @@ func uninstallHelmReleases(settings *cli.EnvSettings) error {
 			if err != nil {
 				return err
 			}
+			report.Step("helm release", cinfo.Release, "uninstalled")
 		}
 		return nil
 	})
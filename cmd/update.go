@@ -0,0 +1,22 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Create common command for updating DeviceChain resources
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update system resources",
+	Long:  `Commands that update DeviceChain system resources and device management entities`,
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+}
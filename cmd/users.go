@@ -0,0 +1,283 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	gql "github.com/devicechain-io/dcctl/graphql"
+	"github.com/devicechain-io/dcctl/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Create common command for managing users
+var usersCmd = &cobra.Command{
+	Use:     "users",
+	Aliases: []string{"user"},
+	Short:   "Manage users",
+	Long:    `Creates, displays and deletes users and manages their passwords and roles`,
+}
+
+// Columns displayed for users.
+var userColumns = []entityColumn{
+	{Header: "USERNAME", Path: "username"},
+	{Header: "EMAIL", Path: "email"},
+	{Header: "FIRST NAME", Path: "firstName", Wide: true},
+	{Header: "LAST NAME", Path: "lastName", Wide: true},
+	{Header: "ENABLED", Path: "enabled"},
+	{Header: "ROLES", Value: entityNames("roles")},
+}
+
+// List of user management entities (users or roles).
+type UmEntityList struct {
+	Items        []interface{} `json:"items"`
+	PageNumber   int           `json:"pageNumber,omitempty"`
+	PageSize     int           `json:"pageSize,omitempty"`
+	TotalRecords int           `json:"totalRecords"`
+	columns      []entityColumn
+}
+
+// Render entities as a table.
+func (list *UmEntityList) Table() *output.Table {
+	return entityTable(list.columns, list.Items)
+}
+
+// Display a list of names (e.g. the roles of a user) as comma-separated values.
+func entityNames(path string) func(fields map[string]interface{}) string {
+	return func(fields map[string]interface{}) string {
+		items, _ := fields[path].([]interface{})
+		names := make([]string, 0, len(items))
+		for _, item := range items {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			} else if named, ok := item.(map[string]interface{}); ok {
+				names = append(names, entityValue(named, "name"))
+			}
+		}
+		return strings.Join(names, ",")
+	}
+}
+
+// Read a new password from stdin or prompt for it twice on the terminal.
+func readNewPassword(fromStdin bool) (string, error) {
+	var password string
+	if fromStdin {
+		read, err := readPassword(true)
+		if err != nil {
+			return "", err
+		}
+		password = read
+	} else {
+		read, err := promptPassword("New password: ")
+		if err != nil {
+			return "", err
+		}
+		confirm, err := promptPassword("Confirm password: ")
+		if err != nil {
+			return "", err
+		}
+		if read != confirm {
+			return "", errors.New("passwords do not match")
+		}
+		password = read
+	}
+	if password == "" {
+		return "", errors.New("password may not be empty")
+	}
+	return password, nil
+}
+
+// Add paging flags to a list command.
+func addPagingFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("page", 1, "page number to display")
+	cmd.Flags().Int("page-size", 100, "number of entities per page")
+}
+
+// Create command that will create a user
+func newUsersCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create username",
+		Short: "Create a user",
+		Long: `Creates a user with the given username. The password is prompted for on the terminal
+unless --password-stdin is passed.`,
+		Example:      `  echo "$PASSWORD" | dcctl users create operator1 --email ops@example.com --role operator --password-stdin`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fromStdin, _ := cmd.Flags().GetBool("password-stdin")
+			disabled, _ := cmd.Flags().GetBool("disabled")
+			roles, _ := cmd.Flags().GetStringSlice("role")
+			password, err := readNewPassword(fromStdin)
+			if err != nil {
+				return err
+			}
+			req := gql.UserCreateRequest{
+				Username:  args[0],
+				Password:  &password,
+				Email:     optionalStringFlag(cmd, "email"),
+				FirstName: optionalStringFlag(cmd, "first-name"),
+				LastName:  optionalStringFlag(cmd, "last-name"),
+				Enabled:   !disabled,
+				Roles:     roles,
+			}
+			um, err := gql.NewUserManagementGraphQLClient(cmd)
+			if err != nil {
+				return err
+			}
+			user, err := um.CreateUser(context.Background(), req)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(output.Progress(), color.HiGreenString("Created user '%s'.\n"), args[0])
+			return output.PrintResult(user)
+		}}
+	cmd.Flags().String("email", "", "email address of user")
+	cmd.Flags().String("first-name", "", "first name of user")
+	cmd.Flags().String("last-name", "", "last name of user")
+	cmd.Flags().StringSlice("role", nil, "role granted to user (may be repeated)")
+	cmd.Flags().Bool("disabled", false, "create the user in a disabled state")
+	cmd.Flags().Bool("password-stdin", false, "read password from stdin")
+	return cmd
+}
+
+// Get the value of a string flag or nil if not set.
+func optionalStringFlag(cmd *cobra.Command, name string) *string {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+	value, _ := cmd.Flags().GetString(name)
+	return &value
+}
+
+// Create command that will list users
+func newUsersListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List users",
+		Long:         `Lists users a page at a time`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			page, _ := cmd.Flags().GetInt("page")
+			size, _ := cmd.Flags().GetInt("page-size")
+			if page < 1 || size < 1 {
+				return errors.New("page and page-size must be greater than zero")
+			}
+			um, err := gql.NewUserManagementGraphQLClient(cmd)
+			if err != nil {
+				return err
+			}
+			users, pagination, err := um.ListUsers(context.Background(), page, size)
+			if err != nil {
+				return err
+			}
+			list := &UmEntityList{Items: entityItems(users), PageNumber: page, PageSize: size, columns: userColumns}
+			if pagination != nil {
+				list.TotalRecords = pagination.GetTotalRecords()
+			}
+			return output.Print(list)
+		}}
+	addPagingFlags(cmd)
+	return cmd
+}
+
+// Create command that will get users by username
+func newUsersGetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "get username...",
+		Short:        "Display users",
+		Long:         `Displays users by username`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			um, err := gql.NewUserManagementGraphQLClient(cmd)
+			if err != nil {
+				return err
+			}
+			users, err := um.GetUsersByUsername(context.Background(), args)
+			if err != nil {
+				return err
+			}
+			list := &UmEntityList{Items: make([]interface{}, 0, len(args)), columns: userColumns}
+			for _, username := range args {
+				user, ok := users[username]
+				if !ok {
+					return fmt.Errorf("user '%s' not found", username)
+				}
+				list.Items = append(list.Items, user)
+			}
+			list.TotalRecords = len(list.Items)
+			return output.Print(list)
+		}}
+}
+
+// Create command that will delete users
+func newUsersDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "delete username...",
+		Short:        "Delete users",
+		Long:         `Deletes users by username`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			um, err := gql.NewUserManagementGraphQLClient(cmd)
+			if err != nil {
+				return err
+			}
+			report := NewOperationReport("delete")
+			for _, username := range args {
+				if _, err := um.DeleteUser(context.Background(), username); err != nil {
+					return fmt.Errorf("unable to delete user '%s': %v", username, err)
+				}
+				report.Step("user", username, "deleted")
+				fmt.Fprintf(output.Progress(), color.HiGreenString("Deleted user '%s'.\n"), username)
+			}
+			return report.Complete()
+		}}
+}
+
+// Create command that will set the password for a user
+func newUsersSetPasswordCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-password username",
+		Short: "Set the password for a user",
+		Long: `Sets the password for an existing user. The password is prompted for on the terminal
+unless --password-stdin is passed.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fromStdin, _ := cmd.Flags().GetBool("password-stdin")
+			password, err := readNewPassword(fromStdin)
+			if err != nil {
+				return err
+			}
+			um, err := gql.NewUserManagementGraphQLClient(cmd)
+			if err != nil {
+				return err
+			}
+			if _, err := um.SetUserPassword(context.Background(), args[0], password); err != nil {
+				return err
+			}
+			fmt.Fprintf(output.Progress(), color.HiGreenString("Password updated for user '%s'.\n"), args[0])
+			return nil
+		}}
+	cmd.Flags().Bool("password-stdin", false, "read password from stdin")
+	return cmd
+}
+
+func init() {
+	addTargetFlags(usersCmd.PersistentFlags())
+	usersCmd.AddCommand(newUsersCreateCommand())
+	usersCmd.AddCommand(newUsersListCommand())
+	usersCmd.AddCommand(newUsersGetCommand())
+	usersCmd.AddCommand(newUsersDeleteCommand())
+	usersCmd.AddCommand(newUsersSetPasswordCommand())
+	rootCmd.AddCommand(usersCmd)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+// Replace stdin with a pipe containing the given input for the duration of a test.
+func setStdin(t *testing.T, input string) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.WriteString(input); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		r.Close()
+	})
+}
+
+func TestReadNewPasswordFromStdin(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "password", input: "s3cret\n", want: "s3cret"},
+		{name: "no trailing newline", input: "s3cret", want: "s3cret"},
+		{name: "empty line", input: "\n", wantErr: true},
+		{name: "carriage return only", input: "\r\n", wantErr: true},
+		{name: "no input", input: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setStdin(t, tt.input)
+			password, err := readNewPassword(true)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if password != tt.want {
+				t.Errorf("password = %q, want %q", password, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntityNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]interface{}
+		want   string
+	}{
+		{name: "named entities", fields: map[string]interface{}{"roles": []interface{}{
+			map[string]interface{}{"name": "admin"}, map[string]interface{}{"name": "operator"}}}, want: "admin,operator"},
+		{name: "strings", fields: map[string]interface{}{"roles": []interface{}{"admin", "viewer"}}, want: "admin,viewer"},
+		{name: "missing", fields: map[string]interface{}{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := entityNames("roles")(tt.fields); got != tt.want {
+				t.Errorf("entityNames = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/devicechain-io/dcctl/output"
 	"github.com/spf13/cobra"
 )
 
@@ -17,8 +18,12 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Get version info",
 	Long:  `Gets version information for the CLI`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if output.Structured() {
+			return output.Print(map[string]string{"gitCommit": gitCommit})
+		}
 		fmt.Println(gitCommit)
+		return nil
 	},
 }
 
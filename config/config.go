@@ -0,0 +1,155 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Default name of configuration file in the user home directory.
+	DefaultFileName = ".dcctl.yaml"
+
+	// Environment variable that may be used to point at a configuration file.
+	EnvConfigFile = "DCCTL_CONFIG"
+)
+
+// Named set of values used to target a DeviceChain environment.
+type Context struct {
+	Server      string `json:"server,omitempty"`
+	Instance    string `json:"instance,omitempty"`
+	Tenant      string `json:"tenant,omitempty"`
+	Kubeconfig  string `json:"kubeconfig,omitempty"`
+	KubeContext string `json:"kubeContext,omitempty"`
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	KeycloakUrl string `json:"keycloakUrl,omitempty"`
+	Realm       string `json:"realm,omitempty"`
+	ClientId    string `json:"clientId,omitempty"`
+	Scheme      string `json:"scheme,omitempty"`
+	CaFile      string `json:"caFile,omitempty"`
+	ClientCert  string `json:"clientCert,omitempty"`
+	ClientKey   string `json:"clientKey,omitempty"`
+	Insecure    string `json:"insecureSkipTlsVerify,omitempty"`
+	Timeout     string `json:"timeout,omitempty"`
+	Proxy       string `json:"proxy,omitempty"`
+	Retries     string `json:"retries,omitempty"`
+}
+
+// Contents of the dcctl configuration file.
+type Config struct {
+	CurrentContext string              `json:"currentContext,omitempty"`
+	Contexts       map[string]*Context `json:"contexts,omitempty"`
+}
+
+// Path of configuration file in use.
+var path string
+
+// Configuration loaded from path (loaded lazily).
+var current *Config
+
+// Set the path used to load and save configuration. An empty path
+// results in the environment variable or default location being used.
+func SetPath(p string) {
+	path = p
+	current = nil
+}
+
+// Get the path used to load and save configuration.
+func GetPath() string {
+	if path != "" {
+		return path
+	}
+	if env := os.Getenv(EnvConfigFile); env != "" {
+		return env
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return DefaultFileName
+	}
+	return filepath.Join(home, DefaultFileName)
+}
+
+// Get the directory where dcctl keeps state such as cached tokens.
+func StateDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".dcctl"), nil
+}
+
+// Get the current configuration, loading it from disk if necessary.
+func Get() (*Config, error) {
+	if current != nil {
+		return current, nil
+	}
+	cfg, err := Load(GetPath())
+	if err != nil {
+		return nil, err
+	}
+	current = cfg
+	return current, nil
+}
+
+// Load configuration from the given path. A missing file results in an empty configuration.
+func Load(p string) (*Config, error) {
+	cfg := &Config{Contexts: make(map[string]*Context)}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	} else if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(b, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse configuration file '%s': %v", p, err)
+	}
+	if cfg.Contexts == nil {
+		cfg.Contexts = make(map[string]*Context)
+	}
+	return cfg, nil
+}
+
+// Save configuration to the configured path.
+func Save(cfg *Config) error {
+	b, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	p := GetPath()
+	err = os.MkdirAll(filepath.Dir(p), 0700)
+	if err != nil {
+		return err
+	}
+	current = cfg
+	return os.WriteFile(p, b, 0600)
+}
+
+// Get the active context or nil if none is selected.
+func (cfg *Config) Active() *Context {
+	if cfg.CurrentContext == "" {
+		return nil
+	}
+	return cfg.Contexts[cfg.CurrentContext]
+}
+
+// Get sorted list of context names.
+func (cfg *Config) ContextNames() []string {
+	names := make([]string, 0, len(cfg.Contexts))
+	for name := range cfg.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,163 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+// Value that may be resolved from a flag, the environment or the active context.
+type Setting struct {
+	Name    string
+	Env     string
+	Default string
+	get     func(*Context) string
+	set     func(*Context, string)
+}
+
+var (
+	Server = &Setting{Name: "server", Env: "DCCTL_SERVER", Default: "localhost",
+		get: func(c *Context) string { return c.Server },
+		set: func(c *Context, v string) { c.Server = v }}
+	Instance = &Setting{Name: "instance", Env: "DCCTL_INSTANCE", Default: "dc1",
+		get: func(c *Context) string { return c.Instance },
+		set: func(c *Context, v string) { c.Instance = v }}
+	Tenant = &Setting{Name: "tenant", Env: "DCCTL_TENANT", Default: "tenant1",
+		get: func(c *Context) string { return c.Tenant },
+		set: func(c *Context, v string) { c.Tenant = v }}
+	Kubeconfig = &Setting{Name: "kubeconfig", Env: "DCCTL_KUBECONFIG",
+		get: func(c *Context) string { return c.Kubeconfig },
+		set: func(c *Context, v string) { c.Kubeconfig = v }}
+	KubeContext = &Setting{Name: "kube-context", Env: "DCCTL_KUBE_CONTEXT",
+		get: func(c *Context) string { return c.KubeContext },
+		set: func(c *Context, v string) { c.KubeContext = v }}
+	Username = &Setting{Name: "username", Env: "DCCTL_USERNAME",
+		get: func(c *Context) string { return c.Username },
+		set: func(c *Context, v string) { c.Username = v }}
+	Password = &Setting{Name: "password", Env: "DCCTL_PASSWORD",
+		get: func(c *Context) string { return c.Password },
+		set: func(c *Context, v string) { c.Password = v }}
+	KeycloakUrl = &Setting{Name: "keycloak-url", Env: "DCCTL_KEYCLOAK_URL",
+		get: func(c *Context) string { return c.KeycloakUrl },
+		set: func(c *Context, v string) { c.KeycloakUrl = v }}
+	Realm = &Setting{Name: "realm", Env: "DCCTL_REALM",
+		get: func(c *Context) string { return c.Realm },
+		set: func(c *Context, v string) { c.Realm = v }}
+	ClientId = &Setting{Name: "client-id", Env: "DCCTL_CLIENT_ID", Default: "dcctl",
+		get: func(c *Context) string { return c.ClientId },
+		set: func(c *Context, v string) { c.ClientId = v }}
+	Scheme = &Setting{Name: "scheme", Env: "DCCTL_SCHEME", Default: "http",
+		get: func(c *Context) string { return c.Scheme },
+		set: func(c *Context, v string) { c.Scheme = v }}
+	CaFile = &Setting{Name: "ca-file", Env: "DCCTL_CA_FILE",
+		get: func(c *Context) string { return c.CaFile },
+		set: func(c *Context, v string) { c.CaFile = v }}
+	ClientCert = &Setting{Name: "client-cert", Env: "DCCTL_CLIENT_CERT",
+		get: func(c *Context) string { return c.ClientCert },
+		set: func(c *Context, v string) { c.ClientCert = v }}
+	ClientKey = &Setting{Name: "client-key", Env: "DCCTL_CLIENT_KEY",
+		get: func(c *Context) string { return c.ClientKey },
+		set: func(c *Context, v string) { c.ClientKey = v }}
+	Insecure = &Setting{Name: "insecure-skip-tls-verify", Env: "DCCTL_INSECURE_SKIP_TLS_VERIFY", Default: "false",
+		get: func(c *Context) string { return c.Insecure },
+		set: func(c *Context, v string) { c.Insecure = v }}
+	Timeout = &Setting{Name: "timeout", Env: "DCCTL_TIMEOUT", Default: "30s",
+		get: func(c *Context) string { return c.Timeout },
+		set: func(c *Context, v string) { c.Timeout = v }}
+	Proxy = &Setting{Name: "proxy", Env: "DCCTL_PROXY",
+		get: func(c *Context) string { return c.Proxy },
+		set: func(c *Context, v string) { c.Proxy = v }}
+	Retries = &Setting{Name: "retries", Env: "DCCTL_RETRIES", Default: "3",
+		get: func(c *Context) string { return c.Retries },
+		set: func(c *Context, v string) { c.Retries = v }}
+)
+
+// All settings that may be stored in a context.
+var Settings = []*Setting{Server, Instance, Tenant, Kubeconfig, KubeContext, Username, Password,
+	KeycloakUrl, Realm, ClientId, Scheme, CaFile, ClientCert, ClientKey, Insecure, Timeout, Proxy, Retries}
+
+// Name of context that overrides the current context for a single invocation.
+var contextOverride string
+
+// Override the current context for this invocation only.
+func SetContextOverride(name string) {
+	contextOverride = name
+}
+
+// Get the context used to resolve settings or nil if none applies.
+func ActiveContext() (*Context, error) {
+	cfg, err := Get()
+	if err != nil {
+		return nil, err
+	}
+	if contextOverride != "" {
+		ctx, ok := cfg.Contexts[contextOverride]
+		if !ok {
+			return nil, fmt.Errorf("context '%s' does not exist", contextOverride)
+		}
+		return ctx, nil
+	}
+	return cfg.Active(), nil
+}
+
+// Get name of context used to resolve settings.
+func ActiveContextName() string {
+	if contextOverride != "" {
+		return contextOverride
+	}
+	cfg, err := Get()
+	if err != nil {
+		return ""
+	}
+	return cfg.CurrentContext
+}
+
+// Find a setting by name.
+func FindSetting(name string) (*Setting, error) {
+	for _, setting := range Settings {
+		if setting.Name == name {
+			return setting, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown context setting '%s'", name)
+}
+
+// Resolve the setting value in order of precedence: flag, environment, active context, default.
+func (s *Setting) Resolve(flags *pflag.FlagSet) string {
+	var flag *pflag.Flag
+	if flags != nil {
+		flag = flags.Lookup(s.Name)
+	}
+	if flag != nil && flag.Changed {
+		return flag.Value.String()
+	}
+	if env := os.Getenv(s.Env); env != "" {
+		return env
+	}
+	if ctx, err := ActiveContext(); err == nil && ctx != nil {
+		if val := s.get(ctx); val != "" {
+			return val
+		}
+	}
+	if flag != nil && flag.DefValue != "" {
+		return flag.DefValue
+	}
+	return s.Default
+}
+
+// Get the value of the setting stored in a context.
+func (s *Setting) Get(ctx *Context) string {
+	return s.get(ctx)
+}
+
+// Store the value of the setting in a context.
+func (s *Setting) Set(ctx *Context, value string) {
+	s.set(ctx, value)
+}
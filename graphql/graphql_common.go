@@ -9,37 +9,93 @@ package graphql
 import (
 	"fmt"
 	"net/http"
-	"time"
+	"sync"
 
 	"github.com/Khan/genqlient/graphql"
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/devicechain-io/dcctl/output"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
 // Gets a GraphQL client based on command flags and other settings.
-func GetGraphQLClientForCommand(cmd *cobra.Command, microservice string) graphql.Client {
-	server, _ := cmd.Flags().GetString("server")
-	instance, _ := cmd.Flags().GetString("instance")
-	tenant, _ := cmd.Flags().GetString("tenant")
-	url := fmt.Sprintf("http://%s/%s/%s/%s/graphql", server, instance, tenant, microservice)
+func GetGraphQLClientForCommand(cmd *cobra.Command, microservice string) (graphql.Client, error) {
+	server := config.Server.Resolve(cmd.Flags())
+	instance := config.Instance.Resolve(cmd.Flags())
+	tenant := config.Tenant.Resolve(cmd.Flags())
 
+	// Timeouts are applied per attempt by the shared transport.
+	transport, opts, err := GetTransportForCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s://%s/%s/%s/%s/graphql", opts.Scheme, server, instance, tenant, microservice)
 	httpClient := http.Client{
-		Timeout: time.Duration(1) * time.Second,
+		Transport: transport,
 	}
-	return graphql.NewClient(url, &httpClient)
+	return graphql.NewClient(url, &httpClient), nil
+}
+
+// Outcome of an assure operation for a single entity.
+type AssureOutcome struct {
+	Kind   string `json:"kind"`
+	Token  string `json:"token"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+const (
+	STATUS_CREATED = "created"
+	STATUS_FOUND   = "found"
+	STATUS_FAILED  = "failed"
+)
+
+// Tracks outcomes of assure operations so they can be reported.
+type AssureTracker struct {
+	mu       sync.Mutex
+	Outcomes []AssureOutcome
 }
 
-// Beginning message for assure operation.
-func assure(model string, token string) {
-	fmt.Print(color.HiWhiteString(fmt.Sprintf("Assure %s '%s' exists: ", model, token)))
+// Create a new tracker for assure operations.
+func NewAssureTracker() *AssureTracker {
+	return &AssureTracker{Outcomes: make([]AssureOutcome, 0)}
 }
 
-// Indicator for entity found.
-func found(token string) {
-	fmt.Println(color.HiGreenString("found"))
+// Record and report the outcome of an assure operation.
+func (t *AssureTracker) track(model string, token string, wascreated bool, err error) {
+	outcome := AssureOutcome{Kind: model, Token: token}
+	var result string
+	if err != nil {
+		outcome.Status = STATUS_FAILED
+		outcome.Error = err.Error()
+		result = color.HiRedString("failed (%s)", err.Error())
+	} else if wascreated {
+		outcome.Status = STATUS_CREATED
+		result = color.HiGreenString("created")
+	} else {
+		outcome.Status = STATUS_FOUND
+		result = color.HiGreenString("found")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Outcomes = append(t.Outcomes, outcome)
+	fmt.Fprintln(output.Progress(), color.HiWhiteString(fmt.Sprintf("Assure %s '%s' exists: ", model, token))+result)
 }
 
-// Indicator for entity created.
-func created(token string) {
-	fmt.Println(color.HiGreenString("created"))
+// Count outcomes by status.
+func (t *AssureTracker) Counts() (created int, found int, failed int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, outcome := range t.Outcomes {
+		switch outcome.Status {
+		case STATUS_CREATED:
+			created++
+		case STATUS_FOUND:
+			found++
+		case STATUS_FAILED:
+			failed++
+		}
+	}
+	return created, found, failed
 }
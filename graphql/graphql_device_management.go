@@ -8,6 +8,7 @@ package graphql
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Khan/genqlient/graphql"
 	dmgql "github.com/devicechain-io/dc-device-management/gqlclient"
@@ -15,24 +16,30 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// Device management client. Assure operations check for existing entities before
+// creating them, so they are marked as safe to retry.
 type DeviceManagementClient struct {
 	graphql.Client
+	Tracker *AssureTracker
 }
 
 // Creates a device management GraphQL client based on command flags and other settings.
-func NewDeviceManagementGraphQLClient(cmd *cobra.Command) DeviceManagementClient {
-	cli := GetGraphQLClientForCommand(cmd, "device-management")
+func NewDeviceManagementGraphQLClient(cmd *cobra.Command) (DeviceManagementClient, error) {
+	cli, err := GetGraphQLClientForCommand(cmd, "device-management")
+	if err != nil {
+		return DeviceManagementClient{}, err
+	}
 	dmclient := DeviceManagementClient{
-		Client: cli,
+		Client:  cli,
+		Tracker: NewAssureTracker(),
 	}
-	return dmclient
+	return dmclient, nil
 }
 
 // Assure a device type (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureDeviceType(ctx context.Context, token string, name *string,
 	description *string, imageUrl *string, icon *string, backgroundColor *string, foregroundColor *string,
-	borderColor *string, metadata *string) {
-	assure("device type", token)
+	borderColor *string, metadata *string) (dmgql.IDeviceType, bool, error) {
 	req := dmmodel.DeviceTypeCreateRequest{
 		Token:           token,
 		Name:            name,
@@ -44,15 +51,9 @@ func (dmc *DeviceManagementClient) AssureDeviceType(ctx context.Context, token s
 		BorderColor:     borderColor,
 		Metadata:        metadata,
 	}
-	resp, wascreated, err := dmgql.AssureDeviceType(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureDeviceType(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("device type", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get device types by token.
@@ -68,8 +69,7 @@ func (dmc *DeviceManagementClient) ListDeviceTypes(ctx context.Context,
 
 // Assure a device (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureDevice(ctx context.Context, token string, deviceTypeToken string, name *string,
-	description *string, metadata *string) {
-	assure("device", token)
+	description *string, metadata *string) (dmgql.IDevice, bool, error) {
 	req := dmmodel.DeviceCreateRequest{
 		Token:           token,
 		DeviceTypeToken: deviceTypeToken,
@@ -77,15 +77,9 @@ func (dmc *DeviceManagementClient) AssureDevice(ctx context.Context, token strin
 		Description:     description,
 		Metadata:        metadata,
 	}
-	resp, wascreated, err := dmgql.AssureDevice(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureDevice(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("device", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get devices by token.
@@ -101,8 +95,7 @@ func (dmc *DeviceManagementClient) ListDevices(ctx context.Context,
 
 // Assure a device relationship type (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureDeviceRelationshipType(ctx context.Context, token string, name *string,
-	description *string, metadata *string, tracked bool) {
-	assure("device relationship type", token)
+	description *string, metadata *string, tracked bool) (dmgql.IDeviceRelationshipType, bool, error) {
 	req := dmmodel.DeviceRelationshipTypeCreateRequest{
 		Token:       token,
 		Name:        name,
@@ -110,15 +103,9 @@ func (dmc *DeviceManagementClient) AssureDeviceRelationshipType(ctx context.Cont
 		Metadata:    metadata,
 		Tracked:     tracked,
 	}
-	resp, wascreated, err := dmgql.AssureDeviceRelationshipType(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureDeviceRelationshipType(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("device relationship type", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get device relationship types by token.
@@ -135,8 +122,7 @@ func (dmc *DeviceManagementClient) ListDeviceRelationshipTypes(ctx context.Conte
 
 // Assure a device relationship (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureDeviceRelationship(ctx context.Context, token string, source string,
-	targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) {
-	assure("device relationship", token)
+	targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) (dmgql.IDeviceRelationship, bool, error) {
 	req := dmmodel.DeviceRelationshipCreateRequest{
 		Token:            token,
 		SourceDevice:     source,
@@ -144,15 +130,9 @@ func (dmc *DeviceManagementClient) AssureDeviceRelationship(ctx context.Context,
 		RelationshipType: relation,
 		Metadata:         metadata,
 	}
-	resp, wascreated, err := dmgql.AssureDeviceRelationship(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureDeviceRelationship(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("device relationship", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get device relationships by token.
@@ -170,8 +150,7 @@ func (dmc *DeviceManagementClient) ListDeviceRelationships(ctx context.Context,
 // Assure a device group (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureDeviceGroup(ctx context.Context, token string, name *string,
 	description *string, imageUrl *string, icon *string, backgroundColor *string, foregroundColor *string,
-	borderColor *string, metadata *string) {
-	assure("device group", token)
+	borderColor *string, metadata *string) (dmgql.IDeviceGroup, bool, error) {
 	req := dmmodel.DeviceGroupCreateRequest{
 		Token:           token,
 		Name:            name,
@@ -183,15 +162,9 @@ func (dmc *DeviceManagementClient) AssureDeviceGroup(ctx context.Context, token
 		BorderColor:     borderColor,
 		Metadata:        metadata,
 	}
-	resp, wascreated, err := dmgql.AssureDeviceGroup(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureDeviceGroup(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("device group", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get device groups by token.
@@ -208,23 +181,16 @@ func (dmc *DeviceManagementClient) ListDeviceGroups(ctx context.Context,
 
 // Assure a device group relationship type (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureDeviceGroupRelationshipType(ctx context.Context,
-	token string, name *string, description *string, metadata *string) {
-	assure("device group relationship type", token)
+	token string, name *string, description *string, metadata *string) (dmgql.IDeviceGroupRelationshipType, bool, error) {
 	req := dmmodel.DeviceGroupRelationshipTypeCreateRequest{
 		Token:       token,
 		Name:        name,
 		Description: description,
 		Metadata:    metadata,
 	}
-	resp, wascreated, err := dmgql.AssureDeviceGroupRelationshipType(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureDeviceGroupRelationshipType(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("device group relationship type", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get device group relationship types by token.
@@ -242,8 +208,7 @@ func (dmc *DeviceManagementClient) ListDeviceGroupRelationshipTypes(ctx context.
 // Assure a device group relationship (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureDeviceGroupRelationship(ctx context.Context,
 	token string, deviceGroup string, targets dmmodel.EntityRelationshipCreateRequest,
-	relation string, metadata *string) {
-	assure("device group relationship", token)
+	relation string, metadata *string) (dmgql.IDeviceGroupRelationship, bool, error) {
 	req := dmmodel.DeviceGroupRelationshipCreateRequest{
 		Token:             token,
 		SourceDeviceGroup: deviceGroup,
@@ -251,15 +216,9 @@ func (dmc *DeviceManagementClient) AssureDeviceGroupRelationship(ctx context.Con
 		RelationshipType:  relation,
 		Metadata:          metadata,
 	}
-	resp, wascreated, err := dmgql.AssureDeviceGroupRelationship(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureDeviceGroupRelationship(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("device group relationship", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get device group relationships by token.
@@ -277,8 +236,7 @@ func (dmc *DeviceManagementClient) ListDeviceGroupRelationships(ctx context.Cont
 // Assure an asset type (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAssetType(ctx context.Context, token string, name *string,
 	description *string, imageUrl *string, icon *string, backgroundColor *string, foregroundColor *string,
-	borderColor *string, metadata *string) {
-	assure("asset type", token)
+	borderColor *string, metadata *string) (dmgql.IAssetType, bool, error) {
 	req := dmmodel.AssetTypeCreateRequest{
 		Token:           token,
 		Name:            name,
@@ -290,15 +248,9 @@ func (dmc *DeviceManagementClient) AssureAssetType(ctx context.Context, token st
 		BorderColor:     borderColor,
 		Metadata:        metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAssetType(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAssetType(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("asset type", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get asset types by token.
@@ -315,8 +267,7 @@ func (dmc *DeviceManagementClient) ListAssetTypes(ctx context.Context,
 
 // Assure an asset (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAsset(ctx context.Context, token string, assetTypeToken string, name *string,
-	description *string, metadata *string) {
-	assure("asset", token)
+	description *string, metadata *string) (dmgql.IAsset, bool, error) {
 	req := dmmodel.AssetCreateRequest{
 		Token:          token,
 		AssetTypeToken: assetTypeToken,
@@ -324,15 +275,9 @@ func (dmc *DeviceManagementClient) AssureAsset(ctx context.Context, token string
 		Description:    description,
 		Metadata:       metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAsset(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAsset(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("asset", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get assets by token.
@@ -349,23 +294,16 @@ func (dmc *DeviceManagementClient) ListAssets(ctx context.Context,
 
 // Assure an asset relationship type (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAssetRelationshipType(ctx context.Context, token string, name *string,
-	description *string, metadata *string) {
-	assure("asset relationship type", token)
+	description *string, metadata *string) (dmgql.IAssetRelationshipType, bool, error) {
 	req := dmmodel.AssetRelationshipTypeCreateRequest{
 		Token:       token,
 		Name:        name,
 		Description: description,
 		Metadata:    metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAssetRelationshipType(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAssetRelationshipType(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("asset relationship type", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get asset relationship types by token.
@@ -382,8 +320,7 @@ func (dmc *DeviceManagementClient) ListAssetRelationshipTypes(ctx context.Contex
 
 // Assure an asset relationship (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAssetRelationship(ctx context.Context, token string, source string,
-	targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) {
-	assure("asset relationship", token)
+	targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) (dmgql.IAssetRelationship, bool, error) {
 	req := dmmodel.AssetRelationshipCreateRequest{
 		Token:            token,
 		SourceAsset:      source,
@@ -391,15 +328,9 @@ func (dmc *DeviceManagementClient) AssureAssetRelationship(ctx context.Context,
 		RelationshipType: relation,
 		Metadata:         metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAssetRelationship(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAssetRelationship(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("asset relationship", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get asset relationships by token.
@@ -417,8 +348,7 @@ func (dmc *DeviceManagementClient) ListAssetRelationships(ctx context.Context,
 // Assure an asset group (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAssetGroup(ctx context.Context, token string, name *string,
 	description *string, imageUrl *string, icon *string, backgroundColor *string, foregroundColor *string,
-	borderColor *string, metadata *string) {
-	assure("asset group", token)
+	borderColor *string, metadata *string) (dmgql.IAssetGroup, bool, error) {
 	req := dmmodel.AssetGroupCreateRequest{
 		Token:           token,
 		Name:            name,
@@ -430,15 +360,9 @@ func (dmc *DeviceManagementClient) AssureAssetGroup(ctx context.Context, token s
 		BorderColor:     borderColor,
 		Metadata:        metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAssetGroup(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAssetGroup(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("asset group", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get asset groups by token.
@@ -454,23 +378,16 @@ func (dmc *DeviceManagementClient) ListAssetGroups(ctx context.Context,
 
 // Assure an asset group relationship type (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAssetGroupRelationshipType(ctx context.Context, token string, name *string,
-	description *string, metadata *string) {
-	assure("asset group relationship type", token)
+	description *string, metadata *string) (dmgql.IAssetGroupRelationshipType, bool, error) {
 	req := dmmodel.AssetGroupRelationshipTypeCreateRequest{
 		Token:       token,
 		Name:        name,
 		Description: description,
 		Metadata:    metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAssetGroupRelationshipType(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAssetGroupRelationshipType(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("asset group relationship type", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get asset group relationship types by token.
@@ -487,8 +404,7 @@ func (dmc *DeviceManagementClient) ListAssetGroupRelationshipTypes(ctx context.C
 
 // Assure an asset group relationship (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAssetGroupRelationship(ctx context.Context, token string, assetGroup string,
-	targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) {
-	assure("asset group relationship", token)
+	targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) (dmgql.IAssetGroupRelationship, bool, error) {
 	req := dmmodel.AssetGroupRelationshipCreateRequest{
 		Token:            token,
 		SourceAssetGroup: assetGroup,
@@ -496,15 +412,9 @@ func (dmc *DeviceManagementClient) AssureAssetGroupRelationship(ctx context.Cont
 		RelationshipType: relation,
 		Metadata:         metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAssetGroupRelationship(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAssetGroupRelationship(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("asset group relationship", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get asset group relationships by token.
@@ -522,8 +432,7 @@ func (dmc *DeviceManagementClient) ListAssetGroupRelationships(ctx context.Conte
 // Assure an area type (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAreaType(ctx context.Context, token string, name *string,
 	description *string, imageUrl *string, icon *string, backgroundColor *string, foregroundColor *string,
-	borderColor *string, metadata *string) {
-	assure("area type", token)
+	borderColor *string, metadata *string) (dmgql.IAreaType, bool, error) {
 	req := dmmodel.AreaTypeCreateRequest{
 		Token:           token,
 		Name:            name,
@@ -535,15 +444,9 @@ func (dmc *DeviceManagementClient) AssureAreaType(ctx context.Context, token str
 		BorderColor:     borderColor,
 		Metadata:        metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAreaType(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAreaType(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("area type", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get area types by token.
@@ -559,8 +462,7 @@ func (dmc *DeviceManagementClient) ListAreaTypes(ctx context.Context,
 
 // Assure an area (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureArea(ctx context.Context, token string, areaTypeToken string, name *string,
-	description *string, metadata *string) {
-	assure("area", token)
+	description *string, metadata *string) (dmgql.IArea, bool, error) {
 	req := dmmodel.AreaCreateRequest{
 		Token:         token,
 		AreaTypeToken: areaTypeToken,
@@ -568,15 +470,9 @@ func (dmc *DeviceManagementClient) AssureArea(ctx context.Context, token string,
 		Description:   description,
 		Metadata:      metadata,
 	}
-	resp, wascreated, err := dmgql.AssureArea(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureArea(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("area", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get areas by token.
@@ -592,23 +488,16 @@ func (dmc *DeviceManagementClient) ListAreas(ctx context.Context,
 
 // Assure an area relationship type (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAreaRelationshipType(ctx context.Context, token string, name *string,
-	description *string, metadata *string) {
-	assure("area relationship type", token)
+	description *string, metadata *string) (dmgql.IAreaRelationshipType, bool, error) {
 	req := dmmodel.AreaRelationshipTypeCreateRequest{
 		Token:       token,
 		Name:        name,
 		Description: description,
 		Metadata:    metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAreaRelationshipType(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAreaRelationshipType(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("area relationship type", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get area relationship types by token.
@@ -625,8 +514,7 @@ func (dmc *DeviceManagementClient) ListAreaRelationshipTypes(ctx context.Context
 
 // Assure an area relationship (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAreaRelationship(ctx context.Context, token string, source string,
-	targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) {
-	assure("area relationship", token)
+	targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) (dmgql.IAreaRelationship, bool, error) {
 	req := dmmodel.AreaRelationshipCreateRequest{
 		Token:            token,
 		SourceArea:       source,
@@ -634,15 +522,9 @@ func (dmc *DeviceManagementClient) AssureAreaRelationship(ctx context.Context, t
 		RelationshipType: relation,
 		Metadata:         metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAreaRelationship(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAreaRelationship(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("area relationship", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get area relationships by token.
@@ -660,8 +542,7 @@ func (dmc *DeviceManagementClient) ListAreaRelationships(ctx context.Context,
 // Assure an area group (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAreaGroup(ctx context.Context, token string, name *string,
 	description *string, imageUrl *string, icon *string, backgroundColor *string, foregroundColor *string,
-	borderColor *string, metadata *string) {
-	assure("area group", token)
+	borderColor *string, metadata *string) (dmgql.IAreaGroup, bool, error) {
 	req := dmmodel.AreaGroupCreateRequest{
 		Token:           token,
 		Name:            name,
@@ -673,15 +554,9 @@ func (dmc *DeviceManagementClient) AssureAreaGroup(ctx context.Context, token st
 		BorderColor:     borderColor,
 		Metadata:        metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAreaGroup(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAreaGroup(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("area group", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get area groups by token.
@@ -698,23 +573,16 @@ func (dmc *DeviceManagementClient) ListAreaGroups(ctx context.Context,
 
 // Assure an area group relationship type (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAreaGroupRelationshipType(ctx context.Context, token string, name *string,
-	description *string, metadata *string) {
-	assure("area group relationship type", token)
+	description *string, metadata *string) (dmgql.IAreaGroupRelationshipType, bool, error) {
 	req := dmmodel.AreaGroupRelationshipTypeCreateRequest{
 		Token:       token,
 		Name:        name,
 		Description: description,
 		Metadata:    metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAreaGroupRelationshipType(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAreaGroupRelationshipType(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("area group relationship type", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get area group relationship types by token.
@@ -731,8 +599,7 @@ func (dmc *DeviceManagementClient) ListAreaGroupRelationshipTypes(ctx context.Co
 
 // Assure an area group relationship (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureAreaGroupRelationship(ctx context.Context, token string, areaGroup string,
-	targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) {
-	assure("area group relationship", token)
+	targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) (dmgql.IAreaGroupRelationship, bool, error) {
 	req := dmmodel.AreaGroupRelationshipCreateRequest{
 		Token:            token,
 		SourceAreaGroup:  areaGroup,
@@ -740,15 +607,9 @@ func (dmc *DeviceManagementClient) AssureAreaGroupRelationship(ctx context.Conte
 		RelationshipType: relation,
 		Metadata:         metadata,
 	}
-	resp, wascreated, err := dmgql.AssureAreaGroupRelationship(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureAreaGroupRelationship(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("area group relationship", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get area group relationships by token.
@@ -766,8 +627,7 @@ func (dmc *DeviceManagementClient) ListAreaGroupRelationships(ctx context.Contex
 // Assure a customer type (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureCustomerType(ctx context.Context, token string, name *string,
 	description *string, imageUrl *string, icon *string, backgroundColor *string, foregroundColor *string,
-	borderColor *string, metadata *string) {
-	assure("customer type", token)
+	borderColor *string, metadata *string) (dmgql.ICustomerType, bool, error) {
 	req := dmmodel.CustomerTypeCreateRequest{
 		Token:           token,
 		Name:            name,
@@ -779,15 +639,9 @@ func (dmc *DeviceManagementClient) AssureCustomerType(ctx context.Context, token
 		BorderColor:     borderColor,
 		Metadata:        metadata,
 	}
-	resp, wascreated, err := dmgql.AssureCustomerType(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureCustomerType(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("customer type", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get customer types by token.
@@ -804,8 +658,7 @@ func (dmc *DeviceManagementClient) ListCustomerTypes(ctx context.Context,
 
 // Assure a customer (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureCustomer(ctx context.Context, token string, customerTypeToken string,
-	name *string, description *string, metadata *string) {
-	assure("customer", token)
+	name *string, description *string, metadata *string) (dmgql.ICustomer, bool, error) {
 	req := dmmodel.CustomerCreateRequest{
 		Token:             token,
 		CustomerTypeToken: customerTypeToken,
@@ -813,15 +666,9 @@ func (dmc *DeviceManagementClient) AssureCustomer(ctx context.Context, token str
 		Description:       description,
 		Metadata:          metadata,
 	}
-	resp, wascreated, err := dmgql.AssureCustomer(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureCustomer(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("customer", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get customers by token.
@@ -838,23 +685,16 @@ func (dmc *DeviceManagementClient) ListCustomers(ctx context.Context,
 
 // Assure a customer relationship type (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureCustomerRelationshipType(ctx context.Context, token string,
-	name *string, description *string, metadata *string) {
-	assure("customer relationship type", token)
+	name *string, description *string, metadata *string) (dmgql.ICustomerRelationshipType, bool, error) {
 	req := dmmodel.CustomerRelationshipTypeCreateRequest{
 		Token:       token,
 		Name:        name,
 		Description: description,
 		Metadata:    metadata,
 	}
-	resp, wascreated, err := dmgql.AssureCustomerRelationshipType(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureCustomerRelationshipType(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("customer relationship type", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get customer relationship types by token.
@@ -871,8 +711,7 @@ func (dmc *DeviceManagementClient) ListCustomerRelationshipTypes(ctx context.Con
 
 // Assure a customer relationship (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureCustomerRelationship(ctx context.Context, token string, source string,
-	targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) {
-	assure("customer relationship", token)
+	targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) (dmgql.ICustomerRelationship, bool, error) {
 	req := dmmodel.CustomerRelationshipCreateRequest{
 		Token:            token,
 		SourceCustomer:   source,
@@ -880,15 +719,9 @@ func (dmc *DeviceManagementClient) AssureCustomerRelationship(ctx context.Contex
 		RelationshipType: relation,
 		Metadata:         metadata,
 	}
-	resp, wascreated, err := dmgql.AssureCustomerRelationship(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureCustomerRelationship(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("customer relationship", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get customer relationships by token.
@@ -906,8 +739,7 @@ func (dmc *DeviceManagementClient) ListCustomerRelationships(ctx context.Context
 // Assure a customer group (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureCustomerGroup(ctx context.Context, token string, name *string,
 	description *string, imageUrl *string, icon *string, backgroundColor *string, foregroundColor *string,
-	borderColor *string, metadata *string) {
-	assure("customer group", token)
+	borderColor *string, metadata *string) (dmgql.ICustomerGroup, bool, error) {
 	req := dmmodel.CustomerGroupCreateRequest{
 		Token:           token,
 		Name:            name,
@@ -919,15 +751,9 @@ func (dmc *DeviceManagementClient) AssureCustomerGroup(ctx context.Context, toke
 		BorderColor:     borderColor,
 		Metadata:        metadata,
 	}
-	resp, wascreated, err := dmgql.AssureCustomerGroup(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureCustomerGroup(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("customer group", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get customer groups by token.
@@ -944,23 +770,16 @@ func (dmc *DeviceManagementClient) ListCustomerGroups(ctx context.Context,
 
 // Assure a customer group relationship type (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureCustomerGroupRelationshipType(ctx context.Context, token string,
-	name *string, description *string, metadata *string) {
-	assure("customer group relationship type", token)
+	name *string, description *string, metadata *string) (dmgql.ICustomerGroupRelationshipType, bool, error) {
 	req := dmmodel.CustomerGroupRelationshipTypeCreateRequest{
 		Token:       token,
 		Name:        name,
 		Description: description,
 		Metadata:    metadata,
 	}
-	resp, wascreated, err := dmgql.AssureCustomerGroupRelationshipType(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureCustomerGroupRelationshipType(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("customer group relationship type", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get customer group relationship types by token.
@@ -977,8 +796,7 @@ func (dmc *DeviceManagementClient) ListCustomerGroupRelationshipTypes(ctx contex
 
 // Assure a customer group relationship (check for existing or create new).
 func (dmc *DeviceManagementClient) AssureCustomerGroupRelationship(ctx context.Context, token string,
-	customerGroup string, targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) {
-	assure("customer group relationship", token)
+	customerGroup string, targets dmmodel.EntityRelationshipCreateRequest, relation string, metadata *string) (dmgql.ICustomerGroupRelationship, bool, error) {
 	req := dmmodel.CustomerGroupRelationshipCreateRequest{
 		Token:               token,
 		SourceCustomerGroup: customerGroup,
@@ -986,15 +804,9 @@ func (dmc *DeviceManagementClient) AssureCustomerGroupRelationship(ctx context.C
 		RelationshipType:    relation,
 		Metadata:            metadata,
 	}
-	resp, wascreated, err := dmgql.AssureCustomerGroupRelationship(ctx, dmc.Client, req)
-	if err != nil {
-		panic(err)
-	}
-	if wascreated {
-		created(resp.GetToken())
-	} else {
-		found(resp.GetToken())
-	}
+	resp, wascreated, err := dmgql.AssureCustomerGroupRelationship(Idempotent(ctx), dmc.Client, req)
+	dmc.Tracker.track("customer group relationship", token, wascreated, err)
+	return resp, wascreated, err
 }
 
 // Get customer group relationships by token.
@@ -1008,3 +820,26 @@ func (dmc *DeviceManagementClient) ListCustomerGroupRelationships(ctx context.Co
 	pageNumber int, pageSize int) ([]dmgql.ICustomerGroupRelationship, *dmgql.DefaultPagination, error) {
 	return dmgql.ListCustomerGroupRelationships(ctx, dmc.Client, pageNumber, pageSize)
 }
+
+// Update an existing entity. Entity is the GraphQL type of the entity (e.g. DeviceType) and
+// request is the create request for the type, which is also used for updates.
+func (dmc *DeviceManagementClient) UpdateEntity(ctx context.Context, entity string, token string,
+	request interface{}) error {
+	query := fmt.Sprintf(`mutation Update%[1]s($token: String!, $request: %[1]sCreateRequest!) {
+  update%[1]s(token: $token, request: $request) {
+    id
+  }
+}`, entity)
+	variables := map[string]interface{}{"token": token, "request": request}
+	return execute(ctx, dmc.Client, "Update"+entity, query, variables, "update"+entity, nil)
+}
+
+// Delete an existing entity. Entity is the GraphQL type of the entity (e.g. DeviceType).
+func (dmc *DeviceManagementClient) DeleteEntity(ctx context.Context, entity string, token string) (bool, error) {
+	query := fmt.Sprintf(`mutation Delete%[1]s($token: String!) {
+  delete%[1]s(token: $token)
+}`, entity)
+	deleted := false
+	err := execute(ctx, dmc.Client, "Delete"+entity, query, map[string]interface{}{"token": token}, "delete"+entity, &deleted)
+	return deleted, err
+}
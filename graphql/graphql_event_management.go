@@ -0,0 +1,104 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+)
+
+// Query for a page of events. Fields specific to each event type are selected with fragments.
+const LIST_EVENTS_QUERY = `query ListEvents($criteria: EventSearchCriteria!) {
+  events(criteria: $criteria) {
+    results {
+      id
+      eventType
+      occurredTime
+      processedTime
+      device {
+        token
+      }
+      deviceAssignment {
+        token
+      }
+      ... on LocationEvent {
+        latitude
+        longitude
+        elevation
+      }
+      ... on MeasurementEvent {
+        name
+        value
+      }
+      ... on AlertEvent {
+        alertType
+        level
+        message
+      }
+      ... on CommandInvocationEvent {
+        command {
+          token
+        }
+        parameters
+      }
+      ... on CommandResponseEvent {
+        originatingEventId
+        response
+      }
+      ... on StateChangeEvent {
+        attribute
+        previousState
+        newState
+      }
+    }
+    pagination {
+      pageStart
+      pageEnd
+      totalRecords
+    }
+  }
+}`
+
+// Event management client.
+type EventManagementClient struct {
+	graphql.Client
+}
+
+// Criteria for searching events. Unset filters match all events.
+type EventSearchCriteria struct {
+	PageNumber      int        `json:"pageNumber"`
+	PageSize        int        `json:"pageSize"`
+	DeviceToken     *string    `json:"deviceToken,omitempty"`
+	AssignmentToken *string    `json:"assignmentToken,omitempty"`
+	EventType       *string    `json:"eventType,omitempty"`
+	StartTime       *time.Time `json:"startTime,omitempty"`
+	EndTime         *time.Time `json:"endTime,omitempty"`
+}
+
+// Creates an event management GraphQL client based on command flags and other settings.
+func NewEventManagementGraphQLClient(cmd *cobra.Command) (EventManagementClient, error) {
+	cli, err := GetGraphQLClientForCommand(cmd, "event-management")
+	if err != nil {
+		return EventManagementClient{}, err
+	}
+	return EventManagementClient{Client: cli}, nil
+}
+
+// List events that meet criteria.
+func (emc *EventManagementClient) ListEvents(ctx context.Context,
+	criteria EventSearchCriteria) ([]map[string]interface{}, *Pagination, error) {
+	results := &searchResults{}
+	err := execute(ctx, emc.Client, "ListEvents", LIST_EVENTS_QUERY, map[string]interface{}{"criteria": criteria},
+		"events", results)
+	if err != nil {
+		return nil, nil, err
+	}
+	return results.Results, results.Pagination, nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Khan/genqlient/graphql"
+)
+
+// Pagination information returned with a page of search results.
+type Pagination struct {
+	PageStart    int `json:"pageStart"`
+	PageEnd      int `json:"pageEnd"`
+	TotalRecords int `json:"totalRecords"`
+}
+
+// Get total number of records matching the search.
+func (p *Pagination) GetTotalRecords() int {
+	return p.TotalRecords
+}
+
+// Page of search results decoded as generic entities.
+type searchResults struct {
+	Results    []map[string]interface{} `json:"results"`
+	Pagination *Pagination              `json:"pagination"`
+}
+
+// Execute an operation defined in dcctl rather than in the generated client of a microservice.
+// The named field of the response data is decoded into result (if not nil).
+func execute(ctx context.Context, cli graphql.Client, opName string, query string,
+	variables map[string]interface{}, field string, result interface{}) error {
+	data := make(map[string]json.RawMessage)
+	err := cli.MakeRequest(ctx, &graphql.Request{OpName: opName, Query: query, Variables: variables},
+		&graphql.Response{Data: &data})
+	if err != nil {
+		return err
+	}
+	raw, ok := data[field]
+	if !ok {
+		return fmt.Errorf("response to %s did not include '%s'", opName, field)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, result)
+}
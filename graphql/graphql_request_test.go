@@ -0,0 +1,179 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Khan/genqlient/graphql"
+	dmmodel "github.com/devicechain-io/dc-device-management/model"
+)
+
+// Client that records requests and answers with canned response data.
+type recordingClient struct {
+	requests []*graphql.Request
+	data     string
+}
+
+func (c *recordingClient) MakeRequest(ctx context.Context, req *graphql.Request, resp *graphql.Response) error {
+	c.requests = append(c.requests, req)
+	return json.Unmarshal([]byte(c.data), resp.Data)
+}
+
+// Get the request variables as they are sent to the server.
+func sentVariables(t *testing.T, req *graphql.Request) map[string]interface{} {
+	b, err := json.Marshal(req.Variables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	variables := make(map[string]interface{})
+	if err := json.Unmarshal(b, &variables); err != nil {
+		t.Fatal(err)
+	}
+	return variables
+}
+
+func TestUpdateEntity(t *testing.T) {
+	cli := &recordingClient{data: `{"updateDeviceType": {"id": "1"}}`}
+	dm := DeviceManagementClient{Client: cli}
+	name := "Sensor"
+	err := dm.UpdateEntity(context.Background(), "DeviceType", "sensor", dmmodel.DeviceTypeCreateRequest{Token: "sensor", Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := cli.requests[0]
+	if req.OpName != "UpdateDeviceType" {
+		t.Errorf("operation = %s", req.OpName)
+	}
+	for _, part := range []string{"$request: DeviceTypeCreateRequest!", "updateDeviceType(token: $token, request: $request)"} {
+		if !strings.Contains(req.Query, part) {
+			t.Errorf("query does not contain %q:\n%s", part, req.Query)
+		}
+	}
+	if token := sentVariables(t, req)["token"]; token != "sensor" {
+		t.Errorf("token variable = %v", token)
+	}
+}
+
+func TestDeleteEntity(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "deleted", data: `{"deleteAsset": true}`, want: true},
+		{name: "not deleted", data: `{"deleteAsset": false}`, want: false},
+		{name: "missing field", data: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &recordingClient{data: tt.data}
+			dm := DeviceManagementClient{Client: cli}
+			deleted, err := dm.DeleteEntity(context.Background(), "Asset", "truck-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if deleted != tt.want {
+				t.Errorf("deleted = %v, want %v", deleted, tt.want)
+			}
+			if !strings.Contains(cli.requests[0].Query, "deleteAsset(token: $token)") {
+				t.Errorf("unexpected query:\n%s", cli.requests[0].Query)
+			}
+		})
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	cli := &recordingClient{data: `{"events": {
+		"results": [{"id": "e1", "eventType": "MEASUREMENT", "device": {"token": "d1"}, "name": "temp", "value": 21.5}],
+		"pagination": {"pageStart": 1, "pageEnd": 1, "totalRecords": 7}}}`}
+	em := EventManagementClient{Client: cli}
+	device := "d1"
+	events, pagination, err := em.ListEvents(context.Background(), EventSearchCriteria{PageNumber: 2, PageSize: 1, DeviceToken: &device})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0]["name"] != "temp" {
+		t.Errorf("unexpected events: %v", events)
+	}
+	if pagination.GetTotalRecords() != 7 {
+		t.Errorf("total records = %d", pagination.GetTotalRecords())
+	}
+	criteria := sentVariables(t, cli.requests[0])["criteria"].(map[string]interface{})
+	if criteria["deviceToken"] != "d1" || criteria["pageNumber"] != float64(2) {
+		t.Errorf("unexpected criteria: %v", criteria)
+	}
+	if _, ok := criteria["assignmentToken"]; ok {
+		t.Error("unset filters should be omitted")
+	}
+}
+
+func TestGetUsersByUsername(t *testing.T) {
+	cli := &recordingClient{data: `{"usersByUsername": [
+		{"id": "1", "username": "alice", "roles": [{"name": "admin"}]},
+		{"id": "2", "username": "bob", "roles": []}]}`}
+	um := UserManagementClient{Client: cli}
+	users, err := um.GetUsersByUsername(context.Background(), []string{"alice", "bob", "carol"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users["alice"]["id"] != "1" || users["bob"]["id"] != "2" {
+		t.Errorf("unexpected users: %v", users)
+	}
+	if _, ok := users["carol"]; ok {
+		t.Error("unknown user should not be indexed")
+	}
+}
+
+func TestChangeRoles(t *testing.T) {
+	tests := []struct {
+		name   string
+		field  string
+		change func(UserManagementClient) (map[string]interface{}, error)
+	}{
+		{name: "grant", field: "grantRoles", change: func(um UserManagementClient) (map[string]interface{}, error) {
+			return um.GrantRoles(context.Background(), "alice", []string{"admin"})
+		}},
+		{name: "revoke", field: "revokeRoles", change: func(um UserManagementClient) (map[string]interface{}, error) {
+			return um.RevokeRoles(context.Background(), "alice", []string{"admin"})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &recordingClient{data: `{"` + tt.field + `": {"username": "alice"}}`}
+			user, err := tt.change(UserManagementClient{Client: cli})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if user["username"] != "alice" {
+				t.Errorf("unexpected user: %v", user)
+			}
+			if !strings.Contains(cli.requests[0].Query, tt.field+"(username: $username, roles: $roles)") {
+				t.Errorf("unexpected query:\n%s", cli.requests[0].Query)
+			}
+		})
+	}
+}
+
+func TestCreateUserOmitsUnsetFields(t *testing.T) {
+	cli := &recordingClient{data: `{"createUser": {"username": "alice"}}`}
+	um := UserManagementClient{Client: cli}
+	if _, err := um.CreateUser(context.Background(), UserCreateRequest{Username: "alice", Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	request := sentVariables(t, cli.requests[0])["request"].(map[string]interface{})
+	if request["username"] != "alice" || request["enabled"] != true {
+		t.Errorf("unexpected request: %v", request)
+	}
+	if _, ok := request["password"]; ok {
+		t.Error("unset password should be omitted")
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devicechain-io/dcctl/auth"
+	"github.com/devicechain-io/dcctl/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// Initial delay between retries.
+	RETRY_BASE_DELAY = 250 * time.Millisecond
+
+	// Maximum delay between retries.
+	RETRY_MAX_DELAY = 5 * time.Second
+)
+
+// Options used to configure the transport for remote calls.
+type TransportOptions struct {
+	Scheme             string
+	CaFile             string
+	ClientCert         string
+	ClientKey          string
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+	Proxy              string
+	Retries            int
+}
+
+// Get transport options based on command flags and other settings.
+func GetTransportOptionsForCommand(cmd *cobra.Command) (*TransportOptions, error) {
+	flags := cmd.Flags()
+	opts := &TransportOptions{
+		Scheme:     config.Scheme.Resolve(flags),
+		CaFile:     config.CaFile.Resolve(flags),
+		ClientCert: config.ClientCert.Resolve(flags),
+		ClientKey:  config.ClientKey.Resolve(flags),
+		Proxy:      config.Proxy.Resolve(flags),
+	}
+	if opts.Scheme != "http" && opts.Scheme != "https" {
+		return nil, fmt.Errorf("invalid scheme '%s' (expected http or https)", opts.Scheme)
+	}
+	var err error
+	opts.InsecureSkipVerify, err = strconv.ParseBool(config.Insecure.Resolve(flags))
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for insecure-skip-tls-verify: %v", err)
+	}
+	opts.Timeout, err = time.ParseDuration(config.Timeout.Resolve(flags))
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for timeout: %v", err)
+	}
+	opts.Retries, err = strconv.Atoi(config.Retries.Resolve(flags))
+	if err != nil || opts.Retries < 0 {
+		return nil, fmt.Errorf("invalid value for retries: %s", config.Retries.Resolve(flags))
+	}
+	return opts, nil
+}
+
+// Create TLS configuration based on transport options.
+func NewTlsConfig(opts *TransportOptions) (*tls.Config, error) {
+	tlsconfig := &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+	if opts.CaFile != "" {
+		pem, err := os.ReadFile(opts.CaFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA bundle: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle '%s'", opts.CaFile)
+		}
+		tlsconfig.RootCAs = pool
+	}
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		if opts.ClientCert == "" || opts.ClientKey == "" {
+			return nil, errors.New("client-cert and client-key must be specified together")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %v", err)
+		}
+		tlsconfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsconfig, nil
+}
+
+// Create the base transport handling TLS and proxy settings.
+func NewBaseTransport(opts *TransportOptions) (*http.Transport, error) {
+	tlsconfig, err := NewTlsConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsconfig
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %v", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	return transport, nil
+}
+
+// Transport shared by all microservice clients.
+var (
+	sharedMu        sync.Mutex
+	sharedTransport http.RoundTripper
+	sharedOptions   *TransportOptions
+)
+
+// Get the transport shared by all microservice clients, creating it on first use. The transport
+// adds bearer tokens for the active context and retries failed calls.
+func GetTransportForCommand(cmd *cobra.Command) (http.RoundTripper, *TransportOptions, error) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+	if sharedTransport != nil {
+		return sharedTransport, sharedOptions, nil
+	}
+
+	opts, err := GetTransportOptionsForCommand(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	base, err := NewBaseTransport(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	server := config.Server.Resolve(cmd.Flags())
+	authed, err := auth.NewTransportForContext(config.ActiveContextName(), opts.Scheme, server, base)
+	if err != nil {
+		return nil, nil, err
+	}
+	sharedTransport = &RetryTransport{Base: authed, Retries: opts.Retries, Timeout: opts.Timeout}
+	sharedOptions = opts
+	return sharedTransport, sharedOptions, nil
+}
+
+// Key for marking a request context as safe to retry.
+type idempotentKey struct{}
+
+// Mark calls made with the context as safe to retry even if they contain mutations.
+func Idempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// Check whether calls made with the context were marked as safe to retry.
+func isIdempotent(ctx context.Context) bool {
+	marked, _ := ctx.Value(idempotentKey{}).(bool)
+	return marked
+}
+
+// Check whether a GraphQL request body only contains a query.
+func isQuery(body []byte) bool {
+	req := struct {
+		Query string `json:"query"`
+	}{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return false
+	}
+	operation := strings.TrimSpace(req.Query)
+	return strings.HasPrefix(operation, "query") || strings.HasPrefix(operation, "{")
+}
+
+// Round tripper that applies a per-attempt timeout and retries queries and idempotent
+// operations with exponential backoff on connection errors and server errors.
+type RetryTransport struct {
+	Base    http.RoundTripper
+	Retries int
+	Timeout time.Duration
+}
+
+// Response body that releases the attempt context when closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close the body and release the attempt context.
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// Execute a single attempt with the configured timeout.
+func (t *RetryTransport) attempt(req *http.Request, body []byte) (*http.Response, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if t.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+	}
+	attempt := req.Clone(ctx)
+	if body != nil {
+		attempt.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := t.Base.RoundTrip(attempt)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// Execute request, retrying if allowed.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	retries := 0
+	if isIdempotent(req.Context()) || isQuery(body) {
+		retries = t.Retries
+	}
+
+	delay := RETRY_BASE_DELAY
+	for try := 0; ; try++ {
+		resp, err := t.attempt(req, body)
+		retryable := err != nil || resp.StatusCode >= 500
+		if !retryable || try >= retries || req.Context().Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > RETRY_MAX_DELAY {
+			delay = RETRY_MAX_DELAY
+		}
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Round tripper that answers attempts with scripted status codes. A status of zero
+// fails the attempt with a connection error.
+type scriptedTransport struct {
+	statuses []int
+	bodies   []string
+}
+
+func (t *scriptedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	t.bodies = append(t.bodies, string(b))
+	status := t.statuses[len(t.statuses)-1]
+	if len(t.bodies) <= len(t.statuses) {
+		status = t.statuses[len(t.bodies)-1]
+	}
+	if status == 0 {
+		return nil, errors.New("connection refused")
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+}
+
+const (
+	TEST_QUERY     = `{"query": "query ListDevices { devices { results { token } } }"}`
+	TEST_SHORTHAND = `{"query": "{ devices { results { token } } }"}`
+	TEST_MUTATION  = `{"query": "mutation CreateDevice { createDevice(request: {token: \"d1\"}) { id } }"}`
+)
+
+func TestIsQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{name: "query", body: TEST_QUERY, want: true},
+		{name: "shorthand query", body: TEST_SHORTHAND, want: true},
+		{name: "leading whitespace", body: `{"query": "\n  query ListDevices { devices { id } }"}`, want: true},
+		{name: "mutation", body: TEST_MUTATION, want: false},
+		{name: "subscription", body: `{"query": "subscription Events { events { id } }"}`, want: false},
+		{name: "invalid json", body: `query`, want: false},
+		{name: "empty", body: ``, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isQuery([]byte(tt.body)); got != tt.want {
+				t.Errorf("isQuery = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		idempotent   bool
+		retries      int
+		statuses     []int
+		wantAttempts int
+		wantStatus   int
+		wantErr      bool
+	}{
+		{name: "query succeeds", body: TEST_QUERY, retries: 3, statuses: []int{200}, wantAttempts: 1, wantStatus: 200},
+		{name: "query retried after server error", body: TEST_QUERY, retries: 3, statuses: []int{503, 200},
+			wantAttempts: 2, wantStatus: 200},
+		{name: "query retried after connection error", body: TEST_SHORTHAND, retries: 3, statuses: []int{0, 200},
+			wantAttempts: 2, wantStatus: 200},
+		{name: "query gives up after retries", body: TEST_QUERY, retries: 2, statuses: []int{500},
+			wantAttempts: 3, wantStatus: 500},
+		{name: "client error not retried", body: TEST_QUERY, retries: 3, statuses: []int{400}, wantAttempts: 1, wantStatus: 400},
+		{name: "retries disabled", body: TEST_QUERY, retries: 0, statuses: []int{0}, wantAttempts: 1, wantErr: true},
+		{name: "mutation not retried", body: TEST_MUTATION, retries: 3, statuses: []int{500, 200},
+			wantAttempts: 1, wantStatus: 500},
+		{name: "mutation not retried after connection error", body: TEST_MUTATION, retries: 3, statuses: []int{0, 200},
+			wantAttempts: 1, wantErr: true},
+		{name: "idempotent mutation retried", body: TEST_MUTATION, idempotent: true, retries: 3, statuses: []int{502, 200},
+			wantAttempts: 2, wantStatus: 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := &scriptedTransport{statuses: tt.statuses}
+			transport := &RetryTransport{Base: base, Retries: tt.retries}
+			ctx := context.Background()
+			if tt.idempotent {
+				ctx = Idempotent(ctx)
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/graphql", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := transport.RoundTrip(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if resp != nil {
+				resp.Body.Close()
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+			}
+			if len(base.bodies) != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", len(base.bodies), tt.wantAttempts)
+			}
+
+			// Every attempt sends the complete request body.
+			for i, body := range base.bodies {
+				if body != tt.body {
+					t.Errorf("attempt %d sent %q", i+1, body)
+				}
+			}
+		})
+	}
+}
+
+// Round tripper that blocks until the request context is done.
+type blockingTransport struct {
+	attempts int
+}
+
+func (t *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.attempts++
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestRetryTransportTimeout(t *testing.T) {
+	base := &blockingTransport{}
+	transport := &RetryTransport{Base: base, Retries: 1, Timeout: 20 * time.Millisecond}
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/graphql", strings.NewReader(TEST_QUERY))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = transport.RoundTrip(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want deadline exceeded", err)
+	}
+
+	// Each attempt has its own timeout, so a timed out query is retried.
+	if base.attempts != 2 {
+		t.Errorf("attempts = %d, want 2", base.attempts)
+	}
+}
+
+func TestRetryTransportCancelled(t *testing.T) {
+	base := &scriptedTransport{statuses: []int{503}}
+	transport := &RetryTransport{Base: base, Retries: 5}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/graphql", strings.NewReader(TEST_QUERY))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+	if len(base.bodies) != 1 {
+		t.Errorf("attempts = %d, want 1 once the request is cancelled", len(base.bodies))
+	}
+}
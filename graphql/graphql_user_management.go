@@ -0,0 +1,234 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// Fields selected for users.
+	USER_FIELDS = `id
+    username
+    email
+    firstName
+    lastName
+    enabled
+    roles {
+      name
+    }`
+
+	// Fields selected for roles.
+	ROLE_FIELDS = `id
+    name
+    description`
+)
+
+// User management client.
+type UserManagementClient struct {
+	graphql.Client
+}
+
+// Request for creating a user.
+type UserCreateRequest struct {
+	Username  string   `json:"username"`
+	Password  *string  `json:"password,omitempty"`
+	Email     *string  `json:"email,omitempty"`
+	FirstName *string  `json:"firstName,omitempty"`
+	LastName  *string  `json:"lastName,omitempty"`
+	Enabled   bool     `json:"enabled"`
+	Roles     []string `json:"roles,omitempty"`
+}
+
+// Request for creating a role.
+type RoleCreateRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+// Creates a user management GraphQL client based on command flags and other settings.
+func NewUserManagementGraphQLClient(cmd *cobra.Command) (UserManagementClient, error) {
+	cli, err := GetGraphQLClientForCommand(cmd, "user-management")
+	if err != nil {
+		return UserManagementClient{}, err
+	}
+	return UserManagementClient{Client: cli}, nil
+}
+
+// Index entities by the value of a key field.
+func indexEntities(entities []map[string]interface{}, key string) map[string]map[string]interface{} {
+	indexed := make(map[string]map[string]interface{})
+	for _, entity := range entities {
+		if value, ok := entity[key].(string); ok {
+			indexed[value] = entity
+		}
+	}
+	return indexed
+}
+
+// Create a new user.
+func (umc *UserManagementClient) CreateUser(ctx context.Context, req UserCreateRequest) (map[string]interface{}, error) {
+	query := fmt.Sprintf(`mutation CreateUser($request: UserCreateRequest!) {
+  createUser(request: $request) {
+    %s
+  }
+}`, USER_FIELDS)
+	user := make(map[string]interface{})
+	err := execute(ctx, umc.Client, "CreateUser", query, map[string]interface{}{"request": req}, "createUser", &user)
+	return user, err
+}
+
+// Get users by username.
+func (umc *UserManagementClient) GetUsersByUsername(ctx context.Context,
+	usernames []string) (map[string]map[string]interface{}, error) {
+	query := fmt.Sprintf(`query GetUsersByUsername($usernames: [String!]!) {
+  usersByUsername(usernames: $usernames) {
+    %s
+  }
+}`, USER_FIELDS)
+	users := make([]map[string]interface{}, 0)
+	err := execute(ctx, umc.Client, "GetUsersByUsername", query, map[string]interface{}{"usernames": usernames},
+		"usersByUsername", &users)
+	if err != nil {
+		return nil, err
+	}
+	return indexEntities(users, "username"), nil
+}
+
+// List users that meet criteria.
+func (umc *UserManagementClient) ListUsers(ctx context.Context,
+	pageNumber int, pageSize int) ([]map[string]interface{}, *Pagination, error) {
+	query := fmt.Sprintf(`query ListUsers($criteria: UserSearchCriteria!) {
+  users(criteria: $criteria) {
+    results {
+      %s
+    }
+    pagination {
+      pageStart
+      pageEnd
+      totalRecords
+    }
+  }
+}`, USER_FIELDS)
+	criteria := map[string]interface{}{"pageNumber": pageNumber, "pageSize": pageSize}
+	results := &searchResults{}
+	err := execute(ctx, umc.Client, "ListUsers", query, map[string]interface{}{"criteria": criteria}, "users", results)
+	if err != nil {
+		return nil, nil, err
+	}
+	return results.Results, results.Pagination, nil
+}
+
+// Delete an existing user.
+func (umc *UserManagementClient) DeleteUser(ctx context.Context, username string) (bool, error) {
+	query := `mutation DeleteUser($username: String!) {
+  deleteUser(username: $username)
+}`
+	deleted := false
+	err := execute(ctx, umc.Client, "DeleteUser", query, map[string]interface{}{"username": username}, "deleteUser", &deleted)
+	return deleted, err
+}
+
+// Set the password for an existing user.
+func (umc *UserManagementClient) SetUserPassword(ctx context.Context, username string, password string) (bool, error) {
+	query := `mutation SetUserPassword($username: String!, $password: String!) {
+  setUserPassword(username: $username, password: $password)
+}`
+	updated := false
+	err := execute(ctx, umc.Client, "SetUserPassword", query,
+		map[string]interface{}{"username": username, "password": password}, "setUserPassword", &updated)
+	return updated, err
+}
+
+// Grant or revoke roles for a user.
+func (umc *UserManagementClient) changeRoles(ctx context.Context, operation string, field string, username string,
+	roles []string) (map[string]interface{}, error) {
+	query := fmt.Sprintf(`mutation %[1]s($username: String!, $roles: [String!]!) {
+  %[2]s(username: $username, roles: $roles) {
+    %[3]s
+  }
+}`, operation, field, USER_FIELDS)
+	user := make(map[string]interface{})
+	err := execute(ctx, umc.Client, operation, query, map[string]interface{}{"username": username, "roles": roles},
+		field, &user)
+	return user, err
+}
+
+// Grant roles to a user.
+func (umc *UserManagementClient) GrantRoles(ctx context.Context, username string, roles []string) (map[string]interface{}, error) {
+	return umc.changeRoles(ctx, "GrantRoles", "grantRoles", username, roles)
+}
+
+// Revoke roles from a user.
+func (umc *UserManagementClient) RevokeRoles(ctx context.Context, username string, roles []string) (map[string]interface{}, error) {
+	return umc.changeRoles(ctx, "RevokeRoles", "revokeRoles", username, roles)
+}
+
+// Create a new role.
+func (umc *UserManagementClient) CreateRole(ctx context.Context, req RoleCreateRequest) (map[string]interface{}, error) {
+	query := fmt.Sprintf(`mutation CreateRole($request: RoleCreateRequest!) {
+  createRole(request: $request) {
+    %s
+  }
+}`, ROLE_FIELDS)
+	role := make(map[string]interface{})
+	err := execute(ctx, umc.Client, "CreateRole", query, map[string]interface{}{"request": req}, "createRole", &role)
+	return role, err
+}
+
+// Get roles by name.
+func (umc *UserManagementClient) GetRolesByName(ctx context.Context, names []string) (map[string]map[string]interface{}, error) {
+	query := fmt.Sprintf(`query GetRolesByName($names: [String!]!) {
+  rolesByName(names: $names) {
+    %s
+  }
+}`, ROLE_FIELDS)
+	roles := make([]map[string]interface{}, 0)
+	err := execute(ctx, umc.Client, "GetRolesByName", query, map[string]interface{}{"names": names}, "rolesByName", &roles)
+	if err != nil {
+		return nil, err
+	}
+	return indexEntities(roles, "name"), nil
+}
+
+// List roles that meet criteria.
+func (umc *UserManagementClient) ListRoles(ctx context.Context,
+	pageNumber int, pageSize int) ([]map[string]interface{}, *Pagination, error) {
+	query := fmt.Sprintf(`query ListRoles($criteria: RoleSearchCriteria!) {
+  roles(criteria: $criteria) {
+    results {
+      %s
+    }
+    pagination {
+      pageStart
+      pageEnd
+      totalRecords
+    }
+  }
+}`, ROLE_FIELDS)
+	criteria := map[string]interface{}{"pageNumber": pageNumber, "pageSize": pageSize}
+	results := &searchResults{}
+	err := execute(ctx, umc.Client, "ListRoles", query, map[string]interface{}{"criteria": criteria}, "roles", results)
+	if err != nil {
+		return nil, nil, err
+	}
+	return results.Results, results.Pagination, nil
+}
+
+// Delete an existing role.
+func (umc *UserManagementClient) DeleteRole(ctx context.Context, name string) (bool, error) {
+	query := `mutation DeleteRole($name: String!) {
+  deleteRole(name: $name)
+}`
+	deleted := false
+	err := execute(ctx, umc.Client, "DeleteRole", query, map[string]interface{}{"name": name}, "deleteRole", &deleted)
+	return deleted, err
+}
@@ -0,0 +1,149 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"sigs.k8s.io/yaml"
+)
+
+// Format used when printing command results.
+type Format string
+
+const (
+	FormatText  Format = ""
+	FormatJson  Format = "json"
+	FormatYaml  Format = "yaml"
+	FormatTable Format = "table"
+	FormatWide  Format = "wide"
+	FormatCsv   Format = "csv"
+)
+
+// All formats that may be passed on the command line.
+var Formats = []Format{FormatJson, FormatYaml, FormatTable, FormatWide, FormatCsv}
+
+// Format selected for the current invocation.
+var format = FormatText
+
+// Set the output format from a command line value.
+func SetFormat(value string) error {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		format = FormatText
+		return nil
+	}
+	for _, f := range Formats {
+		if string(f) == value {
+			format = f
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format '%s' (expected one of %s)", value, FormatNames())
+}
+
+// Get the output format for the current invocation.
+func GetFormat() Format {
+	return format
+}
+
+// Get comma-separated list of supported format names.
+func FormatNames() string {
+	names := make([]string, 0, len(Formats))
+	for _, f := range Formats {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, "|")
+}
+
+// Indicates whether a machine-readable document format was requested.
+func Structured() bool {
+	return format == FormatJson || format == FormatYaml
+}
+
+// Writer used for progress messages. When any output format is requested, progress
+// is written to stderr so that stdout only contains the requested document.
+func Progress() io.Writer {
+	if format == FormatText {
+		return os.Stdout
+	}
+	return os.Stderr
+}
+
+// Disable colors if requested or if stdout is not a terminal.
+func ConfigureColor(noColor bool) {
+	fd := os.Stdout.Fd()
+	if noColor || (!isatty.IsTerminal(fd) && !isatty.IsCygwinTerminal(fd)) {
+		color.NoColor = true
+	}
+}
+
+// Print a value to stdout in the selected format. Values that implement Tabular are
+// printed as tables unless a document format was requested.
+func Print(v interface{}) error {
+	return Fprint(os.Stdout, v)
+}
+
+// Print a value to a writer in the selected format.
+func Fprint(w io.Writer, v interface{}) error {
+	switch format {
+	case FormatJson:
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case FormatYaml:
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	case FormatCsv:
+		tab, ok := v.(Tabular)
+		if !ok {
+			return errors.New("csv output is not supported for this result")
+		}
+		return FprintCsv(w, tab.Table())
+	}
+	if tab, ok := v.(Tabular); ok {
+		return FprintTable(w, tab.Table(), format == FormatWide)
+	}
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// Print the detailed view of a value to stdout. In text format the view is written by the
+// details function. Other formats print the value as Print does.
+func PrintDetails(v interface{}, details func(w io.Writer) error) error {
+	if format == FormatText {
+		return details(os.Stdout)
+	}
+	return Print(v)
+}
+
+// Print the result of a command that already reported progress as text. Nothing
+// is printed unless an output format was requested.
+func PrintResult(v interface{}) error {
+	if format == FormatText {
+		return nil
+	}
+	return Print(v)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Replaces characters that would break column alignment.
+var cellReplacer = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+// Column in a table. Wide columns are only shown in wide format.
+type Column struct {
+	Header string
+	Wide   bool
+}
+
+// Table of values with aligned columns.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+}
+
+// Implemented by values that may be printed as a table.
+type Tabular interface {
+	Table() *Table
+}
+
+// Create a table with the given columns.
+func NewTable(columns ...Column) *Table {
+	return &Table{Columns: columns, Rows: make([][]string, 0)}
+}
+
+// Add a row of values to the table. Values are matched to columns by position.
+func (t *Table) AddRow(values ...string) {
+	t.Rows = append(t.Rows, values)
+}
+
+// Tables are trivially printable as tables.
+func (t *Table) Table() *Table {
+	return t
+}
+
+// Print a table with aligned columns.
+func FprintTable(w io.Writer, t *Table, wide bool) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 3, ' ', 0)
+	headers := make([]string, 0)
+	for _, col := range t.Columns {
+		if wide || !col.Wide {
+			headers = append(headers, col.Header)
+		}
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range t.Rows {
+		values := make([]string, 0)
+		for idx, col := range t.Columns {
+			if !wide && col.Wide {
+				continue
+			}
+			value := ""
+			if idx < len(row) {
+				value = cellReplacer.Replace(row[idx])
+			}
+			values = append(values, value)
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
+// Print a table as comma-separated values. All columns are included.
+func FprintCsv(w io.Writer, t *Table) error {
+	cw := csv.NewWriter(w)
+	headers := make([]string, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		headers = append(headers, col.Header)
+	}
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		values := make([]string, len(t.Columns))
+		copy(values, row)
+		if err := cw.Write(values); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package simulate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	FORMAT_JSON = "json"
+	FORMAT_FLAT = "flat"
+)
+
+// Built-in payload formats.
+var Formats = []string{FORMAT_JSON, FORMAT_FLAT}
+
+// Converts an event into a message payload.
+type Format func(event *Event) ([]byte, error)
+
+// Payload with the event fields and a nested payload object.
+func jsonFormat(event *Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+// Payload with the event payload fields merged into the top level object.
+func flatFormat(event *Event) ([]byte, error) {
+	flat := map[string]interface{}{
+		"device":       event.Device,
+		"eventType":    event.EventType,
+		"occurredTime": event.OccurredTime.Format(time.RFC3339Nano),
+	}
+	for key, value := range event.Payload {
+		if key == "measurements" {
+			if entries, ok := value.(map[string]interface{}); ok {
+				for name, measurement := range entries {
+					flat[name] = measurement
+				}
+				continue
+			}
+		}
+		flat[key] = value
+	}
+	return json.Marshal(flat)
+}
+
+// Get a built-in payload format by name.
+func FormatByName(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case FORMAT_JSON:
+		return jsonFormat, nil
+	case FORMAT_FLAT:
+		return flatFormat, nil
+	}
+	return nil, fmt.Errorf("unknown payload format '%s' (expected one of %s)", name, strings.Join(Formats, "|"))
+}
+
+// Create a format from a Go template file. The template is executed with the event
+// and may use the json function to encode values.
+func TemplateFormat(path string) (Format, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	funcs := template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+		"rfc3339": func(t time.Time) string {
+			return t.Format(time.RFC3339Nano)
+		},
+	}
+	tmpl, err := template.New(path).Funcs(funcs).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse payload template '%s': %v", path, err)
+	}
+	return func(event *Event) ([]byte, error) {
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, event); err != nil {
+			return nil, err
+		}
+		return out.Bytes(), nil
+	}, nil
+}
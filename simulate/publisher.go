@@ -0,0 +1,137 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package simulate
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Publishes message payloads to a topic.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+	Close()
+}
+
+// Settings for connecting to an MQTT broker.
+type MqttOptions struct {
+	Broker    string
+	ClientId  string
+	Username  string
+	Password  string
+	Qos       byte
+	TlsConfig *tls.Config
+	Timeout   time.Duration
+	// Called when the connection is lost or restored.
+	OnConnectionChange func(connected bool, err error)
+}
+
+// Publisher that sends payloads to an MQTT broker. The connection is restored
+// automatically if it is lost.
+type MqttPublisher struct {
+	client  mqtt.Client
+	qos     byte
+	timeout time.Duration
+}
+
+// Connect to an MQTT broker.
+func NewMqttPublisher(ctx context.Context, opts MqttOptions) (*MqttPublisher, error) {
+	if opts.Qos > 2 {
+		return nil, errors.New("qos must be 0, 1 or 2")
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	copts := mqtt.NewClientOptions().
+		AddBroker(opts.Broker).
+		SetClientID(opts.ClientId).
+		SetUsername(opts.Username).
+		SetPassword(opts.Password).
+		SetTLSConfig(opts.TlsConfig).
+		SetConnectTimeout(opts.Timeout).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetMaxReconnectInterval(30 * time.Second).
+		SetCleanSession(true)
+	copts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+		if opts.OnConnectionChange != nil {
+			opts.OnConnectionChange(false, err)
+		}
+	})
+	var connected int32
+	copts.SetOnConnectHandler(func(_ mqtt.Client) {
+		if atomic.SwapInt32(&connected, 1) == 1 && opts.OnConnectionChange != nil {
+			opts.OnConnectionChange(true, nil)
+		}
+	})
+	client := mqtt.NewClient(copts)
+
+	// The first connection must succeed within the timeout.
+	token := client.Connect()
+	if err := waitToken(ctx, token, opts.Timeout); err != nil {
+		client.Disconnect(0)
+		return nil, fmt.Errorf("unable to connect to MQTT broker '%s': %v", opts.Broker, err)
+	}
+	return &MqttPublisher{client: client, qos: opts.Qos, timeout: opts.Timeout}, nil
+}
+
+// Wait for an MQTT operation to complete.
+func waitToken(ctx context.Context, token mqtt.Token, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-timer.C:
+		return errors.New("timed out")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Publish a payload to a topic.
+func (p *MqttPublisher) Publish(ctx context.Context, topic string, payload []byte) error {
+	if !p.client.IsConnectionOpen() {
+		return errors.New("not connected to MQTT broker")
+	}
+	return waitToken(ctx, p.client.Publish(topic, p.qos, false, payload), p.timeout)
+}
+
+// Disconnect from the broker after in-flight messages are sent.
+func (p *MqttPublisher) Close() {
+	p.client.Disconnect(250)
+}
+
+// Publisher that writes payloads to a writer instead of a broker.
+type WriterPublisher struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// Create a publisher that writes each payload on a line prefixed by its topic.
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+// Write a payload.
+func (p *WriterPublisher) Publish(_ context.Context, topic string, payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := fmt.Fprintf(p.w, "%s %s\n", topic, payload)
+	return err
+}
+
+// Nothing to release.
+func (p *WriterPublisher) Close() {}
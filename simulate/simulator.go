@@ -0,0 +1,367 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package simulate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EVENT_TYPE_MEASUREMENT = "measurement"
+	EVENT_TYPE_LOCATION    = "location"
+	EVENT_TYPE_ALERT       = "alert"
+
+	// Approximate number of meters in a degree of latitude.
+	METERS_PER_DEGREE = 111320.0
+
+	// Interval at which due events are scheduled.
+	SCHEDULE_INTERVAL = 10 * time.Millisecond
+)
+
+// Event types that may be simulated.
+var EventTypes = []string{EVENT_TYPE_MEASUREMENT, EVENT_TYPE_LOCATION, EVENT_TYPE_ALERT}
+
+// Synthetic event generated for a device.
+type Event struct {
+	Device       string                 `json:"device"`
+	EventType    string                 `json:"eventType"`
+	OccurredTime time.Time              `json:"occurredTime"`
+	Payload      map[string]interface{} `json:"payload"`
+}
+
+// Options that control a simulation.
+type Options struct {
+	// Tokens of devices that events are generated for.
+	Devices []string
+	// Events per second for each device.
+	Rate float64
+	// Time after which the simulation stops (zero for no limit).
+	Duration time.Duration
+	// Time over which devices are started. Devices start publishing one after another
+	// so that the aggregate rate grows linearly to its target.
+	Ramp time.Duration
+	// Maximum number of events per device (zero for no limit).
+	Count int
+	// Types of events to generate.
+	Types []string
+	// Names of measurements included in measurement events.
+	Measurements []string
+	// Probability that an event is an alert when alerts are enabled along with other types.
+	AlertProbability float64
+	// Starting point for random-walk locations.
+	Latitude  float64
+	Longitude float64
+	// Average distance moved between location events.
+	StepMeters float64
+	// Topic events are published to. The string {device} is replaced by the device token.
+	Topic string
+	// Seed for generated values.
+	Seed int64
+	// Converts events into message payloads.
+	Format Format
+}
+
+// Validate options for a simulation.
+func (o *Options) Validate() error {
+	if len(o.Devices) == 0 {
+		return errors.New("no devices to simulate")
+	}
+	if o.Rate <= 0 {
+		return errors.New("rate must be greater than zero")
+	}
+	if o.Duration < 0 || o.Ramp < 0 || o.Count < 0 {
+		return errors.New("duration, ramp and count may not be negative")
+	}
+	if o.AlertProbability < 0 || o.AlertProbability > 1 {
+		return errors.New("alert probability must be between 0 and 1")
+	}
+	if len(o.Types) == 0 {
+		return errors.New("no event types to simulate")
+	}
+	for _, eventType := range o.Types {
+		if !validEventType(eventType) {
+			return fmt.Errorf("unknown event type '%s' (expected one of %s)", eventType, strings.Join(EventTypes, "|"))
+		}
+	}
+	if o.Format == nil {
+		return errors.New("no payload format")
+	}
+	return nil
+}
+
+// Check whether an event type may be simulated.
+func validEventType(eventType string) bool {
+	for _, known := range EventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Counts of events handled by a simulation.
+type Stats struct {
+	Started  time.Time      `json:"started"`
+	Finished time.Time      `json:"finished"`
+	Sent     int            `json:"sent"`
+	Failed   int            `json:"failed"`
+	ByType   map[string]int `json:"byType"`
+	ByDevice map[string]int `json:"byDevice"`
+	mu       sync.Mutex
+}
+
+// Record the outcome of publishing an event.
+func (s *Stats) record(event *Event, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.Failed++
+		return
+	}
+	s.Sent++
+	s.ByType[event.EventType]++
+	s.ByDevice[event.Device]++
+}
+
+// Get a copy of the current counts.
+func (s *Stats) Snapshot() (sent int, failed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Sent, s.Failed
+}
+
+// State of a simulated device.
+type deviceState struct {
+	token        string
+	latitude     float64
+	longitude    float64
+	elevation    float64
+	measurements map[string]float64
+	generated    int
+	nonAlert     int
+}
+
+// Starting values and step sizes for well-known measurements.
+var measurementDefaults = map[string][2]float64{
+	"temperature": {21, 0.2},
+	"humidity":    {45, 0.5},
+	"pressure":    {1013, 0.3},
+	"battery":     {100, 0.05},
+	"speed":       {50, 2},
+	"rpm":         {1500, 50},
+	"fuel":        {80, 0.1},
+}
+
+// Generates synthetic events for a set of devices and publishes them.
+type Simulator struct {
+	opts      Options
+	publisher Publisher
+	rand      *rand.Rand
+	devices   []*deviceState
+	Stats     *Stats
+	OnPublish func(event *Event, err error)
+}
+
+// Create a simulator that publishes events with the given publisher.
+func NewSimulator(opts Options, publisher Publisher) (*Simulator, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	sim := &Simulator{
+		opts:      opts,
+		publisher: publisher,
+		rand:      rand.New(rand.NewSource(opts.Seed)),
+		Stats:     &Stats{ByType: make(map[string]int), ByDevice: make(map[string]int)},
+	}
+	for _, token := range opts.Devices {
+		device := &deviceState{
+			token:        token,
+			latitude:     opts.Latitude + (sim.rand.Float64()-0.5)*0.02,
+			longitude:    opts.Longitude + (sim.rand.Float64()-0.5)*0.02,
+			elevation:    sim.rand.Float64() * 100,
+			measurements: make(map[string]float64),
+		}
+		for _, name := range opts.Measurements {
+			start := 50.0
+			if defaults, ok := measurementDefaults[name]; ok {
+				start = defaults[0]
+			}
+			device.measurements[name] = start
+		}
+		sim.devices = append(sim.devices, device)
+	}
+	return sim, nil
+}
+
+// Check whether an event type is enabled.
+func (sim *Simulator) enabled(eventType string) bool {
+	for _, current := range sim.opts.Types {
+		if current == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Choose the type of the next event for a device.
+func (sim *Simulator) nextType(device *deviceState) string {
+	others := make([]string, 0, 2)
+	for _, eventType := range []string{EVENT_TYPE_MEASUREMENT, EVENT_TYPE_LOCATION} {
+		if sim.enabled(eventType) {
+			others = append(others, eventType)
+		}
+	}
+	if sim.enabled(EVENT_TYPE_ALERT) && (len(others) == 0 || sim.rand.Float64() < sim.opts.AlertProbability) {
+		return EVENT_TYPE_ALERT
+	}
+	eventType := others[device.nonAlert%len(others)]
+	device.nonAlert++
+	return eventType
+}
+
+// Generate the next event for a device.
+func (sim *Simulator) nextEvent(device *deviceState, now time.Time) *Event {
+	event := &Event{Device: device.token, EventType: sim.nextType(device), OccurredTime: now.UTC()}
+	switch event.EventType {
+	case EVENT_TYPE_MEASUREMENT:
+		entries := make(map[string]interface{}, len(sim.opts.Measurements))
+		for _, name := range sim.opts.Measurements {
+			step := 1.0
+			if defaults, ok := measurementDefaults[name]; ok {
+				step = defaults[1]
+			}
+			device.measurements[name] += sim.rand.NormFloat64() * step
+			entries[name] = math.Round(device.measurements[name]*100) / 100
+		}
+		event.Payload = map[string]interface{}{"measurements": entries}
+	case EVENT_TYPE_LOCATION:
+		step := sim.opts.StepMeters / METERS_PER_DEGREE
+		device.latitude += sim.rand.NormFloat64() * step
+		device.longitude += sim.rand.NormFloat64() * step / math.Cos(device.latitude*math.Pi/180)
+		device.elevation = math.Max(0, device.elevation+sim.rand.NormFloat64())
+		event.Payload = map[string]interface{}{
+			"latitude":  math.Round(device.latitude*1e6) / 1e6,
+			"longitude": math.Round(device.longitude*1e6) / 1e6,
+			"elevation": math.Round(device.elevation*10) / 10,
+		}
+	case EVENT_TYPE_ALERT:
+		alertType := []string{"threshold", "battery", "tamper", "connectivity"}[sim.rand.Intn(4)]
+		level := []string{"info", "warning", "error", "critical"}[sim.rand.Intn(4)]
+		event.Payload = map[string]interface{}{
+			"alertType": alertType,
+			"level":     level,
+			"message":   fmt.Sprintf("Simulated %s %s alert for device %s", level, alertType, device.token),
+		}
+	}
+	device.generated++
+	return event
+}
+
+// Publish an event and record the outcome.
+func (sim *Simulator) publish(ctx context.Context, event *Event) {
+	payload, err := sim.opts.Format(event)
+	if err == nil {
+		topic := strings.ReplaceAll(sim.opts.Topic, "{device}", event.Device)
+		err = sim.publisher.Publish(ctx, topic, payload)
+	}
+	sim.Stats.record(event, err)
+	if sim.OnPublish != nil {
+		sim.OnPublish(event, err)
+	}
+}
+
+// Run the simulation until the duration or count is reached or the context is cancelled.
+// Events are spread evenly across devices at the configured rate.
+func (sim *Simulator) Run(ctx context.Context) *Stats {
+	if sim.opts.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sim.opts.Duration)
+		defer cancel()
+	}
+	ticker := time.NewTicker(SCHEDULE_INTERVAL)
+	defer ticker.Stop()
+
+	sim.Stats.Started = time.Now()
+	scheduled := 0
+	next := 0
+	for {
+		now := time.Now()
+		elapsed := now.Sub(sim.Stats.Started).Seconds()
+		due := int(sim.expected(elapsed)) + 1
+		active := sim.active(elapsed)
+		for ; scheduled < due; scheduled++ {
+			// Events still due when the simulation stops are not published.
+			if ctx.Err() != nil {
+				break
+			}
+			device := sim.devices[next%active]
+			next++
+			if sim.opts.Count > 0 && device.generated >= sim.opts.Count {
+				if sim.done() {
+					sim.Stats.Finished = time.Now()
+					return sim.Stats
+				}
+				continue
+			}
+			sim.publish(ctx, sim.nextEvent(device, now))
+		}
+		if sim.opts.Count > 0 && sim.done() {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			sim.Stats.Finished = time.Now()
+			return sim.Stats
+		case <-ticker.C:
+		}
+	}
+	sim.Stats.Finished = time.Now()
+	return sim.Stats
+}
+
+// Number of events expected to be generated after the given number of seconds.
+func (sim *Simulator) expected(elapsed float64) float64 {
+	total := sim.opts.Rate * float64(len(sim.devices))
+	ramp := sim.opts.Ramp.Seconds()
+	if ramp <= 0 {
+		return total * elapsed
+	}
+	if elapsed < ramp {
+		return total * elapsed * elapsed / (2 * ramp)
+	}
+	return total * (elapsed - ramp/2)
+}
+
+// Number of devices publishing after the given number of seconds.
+func (sim *Simulator) active(elapsed float64) int {
+	ramp := sim.opts.Ramp.Seconds()
+	if ramp <= 0 || elapsed >= ramp {
+		return len(sim.devices)
+	}
+	active := int(math.Ceil(float64(len(sim.devices)) * elapsed / ramp))
+	if active < 1 {
+		return 1
+	}
+	return active
+}
+
+// Check whether every device has generated the configured number of events.
+func (sim *Simulator) done() bool {
+	for _, device := range sim.devices {
+		if device.generated < sim.opts.Count {
+			return false
+		}
+	}
+	return true
+}
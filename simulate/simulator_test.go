@@ -0,0 +1,195 @@
+/*
+Copyright © 2022 SiteWhere LLC - All Rights Reserved
+Unauthorized copying of this file, via any medium is strictly prohibited.
+Proprietary and confidential.
+*/
+
+package simulate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Options for a simulation of the given devices with payloads in JSON format.
+func testOptions(devices ...string) Options {
+	return Options{
+		Devices:          devices,
+		Rate:             1000,
+		Types:            []string{EVENT_TYPE_MEASUREMENT, EVENT_TYPE_LOCATION, EVENT_TYPE_ALERT},
+		Measurements:     []string{"temperature", "humidity"},
+		AlertProbability: 0.1,
+		Latitude:         33.75,
+		Longitude:        -84.39,
+		StepMeters:       10,
+		Topic:            "devicechain/{device}/events",
+		Seed:             1,
+		Format:           jsonFormat,
+	}
+}
+
+// Publisher that takes time to publish and fails once the context is done.
+type slowPublisher struct {
+	delay time.Duration
+}
+
+func (p *slowPublisher) Publish(ctx context.Context, _ string, _ []byte) error {
+	time.Sleep(p.delay)
+	return ctx.Err()
+}
+
+func (p *slowPublisher) Close() {}
+
+func TestRunCount(t *testing.T) {
+	opts := testOptions("d1", "d2", "d3")
+	opts.Count = 5
+	var out bytes.Buffer
+	sim, err := NewSimulator(opts, NewWriterPublisher(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := sim.Run(context.Background())
+	if stats.Sent != 15 || stats.Failed != 0 {
+		t.Errorf("sent %d, failed %d", stats.Sent, stats.Failed)
+	}
+	for _, device := range opts.Devices {
+		if stats.ByDevice[device] != 5 {
+			t.Errorf("device %s sent %d events", device, stats.ByDevice[device])
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 15 {
+		t.Fatalf("wrote %d payloads", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "devicechain/d1/events {") {
+		t.Errorf("unexpected payload: %s", lines[0])
+	}
+}
+
+func TestRunRate(t *testing.T) {
+	opts := testOptions("d1", "d2")
+	opts.Rate = 50
+	opts.Duration = 500 * time.Millisecond
+	sim, err := NewSimulator(opts, NewWriterPublisher(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := sim.Run(context.Background())
+
+	// Two devices at 50 events per second for half a second.
+	if stats.Sent < 35 || stats.Sent > 60 {
+		t.Errorf("sent %d events, expected about 50", stats.Sent)
+	}
+	if stats.Failed != 0 {
+		t.Errorf("failed %d events", stats.Failed)
+	}
+}
+
+func TestRunStopsPublishingWhenDone(t *testing.T) {
+	tests := []struct {
+		name   string
+		cancel func(opts *Options) (context.Context, context.CancelFunc)
+	}{
+		{name: "duration", cancel: func(opts *Options) (context.Context, context.CancelFunc) {
+			opts.Duration = 100 * time.Millisecond
+			return context.WithCancel(context.Background())
+		}},
+		{name: "cancelled", cancel: func(opts *Options) (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 100*time.Millisecond)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testOptions("d1")
+			ctx, cancel := tt.cancel(&opts)
+			defer cancel()
+
+			// Publishing falls behind the rate, so events are still due when the simulation stops.
+			sim, err := NewSimulator(opts, &slowPublisher{delay: 20 * time.Millisecond})
+			if err != nil {
+				t.Fatal(err)
+			}
+			stats := sim.Run(ctx)
+			if stats.Sent == 0 {
+				t.Error("no events sent")
+			}
+			if stats.Failed > 1 {
+				t.Errorf("%d events counted as failed after the simulation stopped", stats.Failed)
+			}
+		})
+	}
+}
+
+func TestRampSchedule(t *testing.T) {
+	opts := testOptions("d1", "d2", "d3", "d4")
+	opts.Rate = 10
+	opts.Ramp = 4 * time.Second
+	sim, err := NewSimulator(opts, NewWriterPublisher(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		elapsed  float64
+		expected float64
+		active   int
+	}{
+		{elapsed: 0, expected: 0, active: 1},
+		{elapsed: 1, expected: 5, active: 1},
+		{elapsed: 2, expected: 20, active: 2},
+		{elapsed: 3, expected: 45, active: 3},
+		{elapsed: 4, expected: 80, active: 4},
+		{elapsed: 5, expected: 120, active: 4},
+	}
+	for _, tt := range tests {
+		if expected := sim.expected(tt.elapsed); expected != tt.expected {
+			t.Errorf("expected(%v) = %v, want %v", tt.elapsed, expected, tt.expected)
+		}
+		if active := sim.active(tt.elapsed); active != tt.active {
+			t.Errorf("active(%v) = %d, want %d", tt.elapsed, active, tt.active)
+		}
+	}
+}
+
+// Collect the payloads generated by a simulation with the given seed.
+func seededPayloads(t *testing.T, seed int64) []map[string]interface{} {
+	opts := testOptions("d1", "d2")
+	opts.Count = 20
+	opts.Seed = seed
+	sim, err := NewSimulator(opts, NewWriterPublisher(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payloads := make([]map[string]interface{}, 0)
+	sim.OnPublish = func(event *Event, err error) {
+		b, merr := json.Marshal(event.Payload)
+		if merr != nil {
+			t.Fatal(merr)
+		}
+		payload := make(map[string]interface{})
+		if merr := json.Unmarshal(b, &payload); merr != nil {
+			t.Fatal(merr)
+		}
+		payload["eventType"] = event.EventType
+		payloads = append(payloads, payload)
+	}
+	sim.Run(context.Background())
+	return payloads
+}
+
+func TestSeededPayloads(t *testing.T) {
+	first := seededPayloads(t, 42)
+	if len(first) != 40 {
+		t.Fatalf("generated %d payloads", len(first))
+	}
+	if second := seededPayloads(t, 42); !reflect.DeepEqual(first, second) {
+		t.Error("payloads differ for the same seed")
+	}
+	if other := seededPayloads(t, 43); reflect.DeepEqual(first, other) {
+		t.Error("payloads are the same for different seeds")
+	}
+}